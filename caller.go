@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// CallerSkipper is an optional interface that a vendor Logger reporting callers
+// should implement, so that helpers wrapping the logging methods could report
+// the real call sites.
+//
+// A vendor must report the caller following the rule below:
+// by default, the reported caller is the function calling the logging method
+// (Debug, Infow, Errorf and so on) of the Logger directly.
+// Every WithCallerSkip call on a Logger, or on the Loggers it derived from,
+// adds its skip to the number of frames above that function to skip.
+// Loggers derived via WithField or WithGroup keep the skip of their parents.
+//
+// For vendors calling CaptureCaller right in their logging methods, the skip parameter
+// should be the accumulated skip plus 1, which skips the logging method itself.
+type CallerSkipper interface {
+	// WithCallerSkip returns a new Logger that skips extra skip frames when reporting callers,
+	// in addition to the skip of current Logger.
+	// This method should never return nil.
+	WithCallerSkip(skip int) Logger
+}
+
+// AddCallerSkip returns a Logger that skips extra skip frames when reporting callers.
+// Helpers wrapping the logging methods should use it to report their callers,
+// rather than themselves, as call sites.
+//
+// If logger does not implement CallerSkipper, or skip is 0, logger is returned as is.
+func AddCallerSkip(logger Logger, skip int) Logger {
+	if skip == 0 {
+		return logger
+	}
+	if cs, ok := logger.(CallerSkipper); ok {
+		return cs.WithCallerSkip(skip)
+	}
+	return logger
+}
+
+// Caller is a call site.
+type Caller struct {
+	// File is the full file path of the call site.
+	File string
+	// Function is the full function name of the call site.
+	Function string
+	// PC is the program counter of the call site.
+	PC uintptr
+	// Line is the line number of the call site.
+	Line int
+	// Defined reports whether the call site is known.
+	Defined bool
+}
+
+// CaptureCaller captures the call site of the current goroutine.
+// Skip is the number of frames to skip, 0 means the caller of CaptureCaller.
+func CaptureCaller(skip int) Caller {
+	if skip < 0 {
+		skip = 0
+	}
+	pcs := make([]uintptr, 1)
+	// skip runtime.Callers and CaptureCaller
+	if runtime.Callers(skip+2, pcs) < 1 {
+		return Caller{}
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	if frame.PC == 0 {
+		return Caller{}
+	}
+	return Caller{
+		File:     frame.File,
+		Function: frame.Function,
+		PC:       frame.PC,
+		Line:     frame.Line,
+		Defined:  true,
+	}
+}
+
+// String returns the call site in form of "dir/file.go:line", or "undefined".
+func (c Caller) String() string {
+	if !c.Defined {
+		return "undefined"
+	}
+	return shortPath(c.File) + ":" + strconv.Itoa(c.Line)
+}
+
+// FullPath returns the call site in form of "/full/path/to/file.go:line", or "undefined".
+func (c Caller) FullPath() string {
+	if !c.Defined {
+		return "undefined"
+	}
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
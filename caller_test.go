@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tcl struct {
+	tl
+	skip int
+}
+
+func (t *tcl) WithCallerSkip(skip int) Logger {
+	return &tcl{tl: t.tl, skip: t.skip + skip}
+}
+
+func TestAddCallerSkip(t *testing.T) {
+	t.Run("zero", func(t *testing.T) {
+		l := &tcl{}
+		assert.Same(t, l, AddCallerSkip(l, 0))
+	})
+	t.Run("caller_skipper", func(t *testing.T) {
+		l := AddCallerSkip(AddCallerSkip(&tcl{}, 1), 2).(*tcl)
+		assert.Equal(t, 3, l.skip)
+	})
+	t.Run("not_caller_skipper", func(t *testing.T) {
+		l := &tl{}
+		assert.Same(t, l, AddCallerSkip(l, 1))
+	})
+}
+
+func callerHelper(skip int) Caller {
+	return CaptureCaller(skip)
+}
+
+func TestCaptureCaller(t *testing.T) {
+	c := callerHelper(0)
+	assert.True(t, c.Defined)
+	assert.Equal(t, "github.com/yimi-go/logging.callerHelper", c.Function)
+	assert.NotZero(t, c.PC)
+	assert.Regexp(t, regexp.MustCompile(`^[^/]+/caller_test.go:\d+$`), c.String())
+	assert.Regexp(t, regexp.MustCompile(`^/.+/caller_test.go:\d+$`), c.FullPath())
+
+	c = callerHelper(1)
+	assert.Equal(t, "github.com/yimi-go/logging.TestCaptureCaller", c.Function)
+	assert.Equal(t, "github.com/yimi-go/logging.TestCaptureCaller", CaptureCaller(-1).Function)
+
+	c = CaptureCaller(100)
+	assert.False(t, c.Defined)
+	assert.Equal(t, "undefined", c.String())
+	assert.Equal(t, "undefined", c.FullPath())
+}
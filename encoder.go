@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"reflect"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+type encoderMap map[reflect.Type]func(key string, value any) Field
+
+var (
+	encoderMu    sync.Mutex
+	encoderStore atomic.Value
+)
+
+func init() {
+	encoderStore.Store(encoderMap{})
+}
+
+// RegisterEncoder registers encode for values of type T arriving via Any, i.e. Fields of UnknownType,
+// so that all vendors render them identically, e.g.:
+//
+//	logging.RegisterEncoder(func(key string, value net.IP) logging.Field {
+//		return logging.String(key, value.String())
+//	})
+//
+// T should be a concrete type, and T and *T are different types to register.
+// A nil encode unregisters the encoder of T. Registering is safe for concurrent use,
+// but it is expected to be done at initialization.
+//
+// The registered encoders are applied by Encode and Visit, and take precedence when
+// inferring FieldTypes in Lazy and Struct.
+func RegisterEncoder[T any](encode func(key string, value T) Field) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	old := encoderStore.Load().(encoderMap)
+	encoders := make(encoderMap, len(old)+1)
+	for t, enc := range old {
+		encoders[t] = enc
+	}
+	if encode == nil {
+		delete(encoders, typ)
+	} else {
+		encoders[typ] = func(key string, value any) Field {
+			return encode(key, value.(T))
+		}
+	}
+	encoderStore.Store(encoders)
+}
+
+// Encode returns the Field encoded by the encoder registered for the type of the value,
+// if f is a Field of UnknownType. Otherwise, f is returned.
+//
+// Vendors not visiting Fields via Visit should call Encode before rendering Fields of UnknownType.
+func Encode(f Field) Attr {
+	a := AttrOf(f)
+	if a.typ != UnknownType || a.iface == nil {
+		return a
+	}
+	encoders := encoderStore.Load().(encoderMap)
+	if len(encoders) == 0 {
+		return a
+	}
+	encode, ok := encoders[reflect.TypeOf(a.iface)]
+	if !ok {
+		return a
+	}
+	return Resolve(encode(a.key, a.iface))
+}
+
+// hasEncoder reports whether an encoder is registered for values of type t.
+func hasEncoder(t reflect.Type) bool {
+	_, ok := encoderStore.Load().(encoderMap)[t]
+	return ok
+}
@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// maxErrorDepth limits how deep the error helpers walk into error trees,
+// which also protects them from errors that unwrap to themselves.
+const maxErrorDepth = 32
+
+// ErrorFielder is an interface that errors could implement to carry structured Fields.
+//
+// When such an error is logged via Error or NamedError Field, the carried Fields are
+// expected to be output along with the error. Vendors get them via ErrorFields.
+type ErrorFielder interface {
+	// LogFields returns the Fields carried by the error.
+	LogFields() []Field
+}
+
+// ErrorStacker is an interface that errors could implement to expose the stacktrace
+// captured when they were created.
+//
+// The errors of github.com/pkg/errors, exposing the stacktraces via "StackTrace() errors.StackTrace",
+// are supported as well, so are the other errors whose StackTrace methods return slices of uintptr
+// kinds filled by runtime.Callers.
+type ErrorStacker interface {
+	// Callers returns the program counters of the stacktrace, as runtime.Callers fills.
+	Callers() []uintptr
+}
+
+// ErrorFields collects the Fields carried by err and all the errors it wraps,
+// in depth-first order, which means the outer errors come first.
+//
+// Both errors wrapping a single error via "Unwrap() error" and errors wrapping multiple
+// errors via "Unwrap() []error", like the ones created by errors.Join, are walked.
+func ErrorFields(err error) []Field {
+	var fields []Field
+	walkError(err, 0, func(err error) {
+		if ef, ok := err.(ErrorFielder); ok {
+			fields = append(fields, ef.LogFields()...)
+		}
+	})
+	return fields
+}
+
+func walkError(err error, depth int, fn func(err error)) {
+	if err == nil || depth >= maxErrorDepth {
+		return
+	}
+	fn(err)
+	for _, cause := range unwrapError(err) {
+		walkError(cause, depth+1, fn)
+	}
+}
+
+func unwrapError(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// ErrorChain returns err and the errors it wraps via "Unwrap() error", outermost first.
+// Errors wrapping multiple errors end the chain, use ErrorDetail to render them.
+func ErrorChain(err error) []error {
+	var chain []error
+	for err != nil && len(chain) < maxErrorDepth {
+		chain = append(chain, err)
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return chain
+}
+
+// ErrorDetail creates a Field of GroupType value describing err and the errors it wraps,
+// for vendors that want to output errors in detail. The group consists of:
+//   - message: the error message.
+//   - type: the Go type of the error.
+//   - stack: the stacktrace, if the error implements ErrorStacker or the form of github.com/pkg/errors.
+//   - fields: the Fields carried by the error itself, if it implements ErrorFielder.
+//   - cause: the detail of the wrapped error, if the error wraps a single error.
+//   - causes: the details of the wrapped errors keyed by their indexes,
+//     if the error wraps multiple errors.
+//
+// If err is nil, a Field of UnknownType with nil value is returned.
+func ErrorDetail(key string, err error) Field {
+	if err == nil {
+		return Any(key, nil)
+	}
+	return errorDetail(key, err, 0)
+}
+
+func errorDetail(key string, err error, depth int) Field {
+	fields := []Field{
+		String("message", err.Error()),
+		String("type", fmt.Sprintf("%T", err)),
+	}
+	if pcs := ErrorCallers(err); len(pcs) > 0 {
+		fields = append(fields, String("stack", (&Stacktrace{pcs: pcs}).String()))
+	}
+	if ef, ok := err.(ErrorFielder); ok {
+		if carried := ef.LogFields(); len(carried) > 0 {
+			fields = append(fields, Group("fields", carried...))
+		}
+	}
+	if depth+1 < maxErrorDepth {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := u.Unwrap(); cause != nil {
+				fields = append(fields, errorDetail("cause", cause, depth+1))
+			}
+		case interface{ Unwrap() []error }:
+			var causes []Field
+			for _, cause := range u.Unwrap() {
+				if cause != nil {
+					causes = append(causes, errorDetail(strconv.Itoa(len(causes)), cause, depth+1))
+				}
+			}
+			if len(causes) > 0 {
+				fields = append(fields, Group("causes", causes...))
+			}
+		}
+	}
+	return Group(key, fields...)
+}
+
+// ErrorCallers returns the program counters of the stacktrace of err itself,
+// if err implements ErrorStacker or the form of github.com/pkg/errors, otherwise nil.
+func ErrorCallers(err error) []uintptr {
+	if es, ok := err.(ErrorStacker); ok {
+		return es.Callers()
+	}
+	m, ok := reflect.TypeOf(err).MethodByName("StackTrace")
+	if !ok || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 {
+		return nil
+	}
+	out := m.Type.Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := m.Func.Call([]reflect.Value{reflect.ValueOf(err)})[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
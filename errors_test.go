@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tFieldError struct {
+	cause  error
+	msg    string
+	fields []Field
+	pcs    []uintptr
+}
+
+func (e *tFieldError) Error() string      { return e.msg }
+func (e *tFieldError) Unwrap() error      { return e.cause }
+func (e *tFieldError) LogFields() []Field { return e.fields }
+func (e *tFieldError) Callers() []uintptr { return e.pcs }
+
+// tFrame and tStackTrace mimic the ones of github.com/pkg/errors.
+type tFrame uintptr
+type tStackTrace []tFrame
+
+type tStackError struct {
+	pcs []uintptr
+}
+
+func (e *tStackError) Error() string { return "stack" }
+func (e *tStackError) StackTrace() tStackTrace {
+	trace := make(tStackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		trace[i] = tFrame(pc)
+	}
+	return trace
+}
+
+// tBadStackError has a StackTrace method of another form.
+type tBadStackError struct{}
+
+func (e tBadStackError) Error() string        { return "bad" }
+func (e tBadStackError) StackTrace() []string { return []string{"frame"} }
+
+type tSelfError struct{}
+
+func (e *tSelfError) Error() string { return "self" }
+func (e *tSelfError) Unwrap() error { return e }
+
+func TestErrorFields(t *testing.T) {
+	inner := &tFieldError{msg: "inner", cause: io.EOF, fields: []Field{Int("user_id", 1)}}
+	outer := &tFieldError{msg: "outer", cause: fmt.Errorf("wrap: %w", inner), fields: []Field{Int("order_id", 2)}}
+	other := &tFieldError{msg: "other", fields: []Field{sf("k", "v")}}
+	assert.Nil(t, ErrorFields(nil))
+	assert.Nil(t, ErrorFields(io.EOF))
+	assert.Equal(t, []Field{Int("order_id", 2), Int("user_id", 1)}, ErrorFields(outer))
+	assert.Equal(t, []Field{Int("order_id", 2), Int("user_id", 1), sf("k", "v")},
+		ErrorFields(errors.Join(outer, nil, other)))
+	assert.Empty(t, ErrorFields(&tSelfError{}))
+}
+
+func TestErrorChain(t *testing.T) {
+	wrapped := fmt.Errorf("wrap: %w", io.EOF)
+	assert.Nil(t, ErrorChain(nil))
+	assert.Equal(t, []error{io.EOF}, ErrorChain(io.EOF))
+	assert.Equal(t, []error{wrapped, io.EOF}, ErrorChain(wrapped))
+	joined := errors.Join(io.EOF)
+	assert.Equal(t, []error{joined}, ErrorChain(joined))
+	assert.Len(t, ErrorChain(&tSelfError{}), maxErrorDepth)
+}
+
+func TestErrorDetail(t *testing.T) {
+	assert.Equal(t, Any("error", nil), ErrorDetail("error", nil))
+
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	inner := &tFieldError{msg: "inner", fields: []Field{Int("user_id", 1)}, pcs: pcs}
+	joined := errors.Join(inner, io.EOF)
+	wrapped := fmt.Errorf("wrap: %w", joined)
+	f := ErrorDetail("error", wrapped)
+	want := Group("error",
+		sf("message", wrapped.Error()),
+		sf("type", "*fmt.wrapError"),
+		Group("cause",
+			sf("message", joined.Error()),
+			sf("type", "*errors.joinError"),
+			Group("causes",
+				Group("0",
+					sf("message", "inner"),
+					sf("type", "*logging.tFieldError"),
+					sf("stack", (&Stacktrace{pcs: pcs}).String()),
+					Group("fields", Int("user_id", 1)),
+				),
+				Group("1",
+					sf("message", "EOF"),
+					sf("type", "*errors.errorString"),
+				),
+			),
+		),
+	)
+	assert.Equal(t, want, f)
+}
+
+func TestErrorDetail_pkgErrors(t *testing.T) {
+	pcs := make([]uintptr, 2)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	f := ErrorDetail("error", &tStackError{pcs: pcs})
+	assert.Equal(t, Group("error",
+		sf("message", "stack"),
+		sf("type", "*logging.tStackError"),
+		sf("stack", (&Stacktrace{pcs: pcs}).String()),
+	), f)
+
+	f = ErrorDetail("error", tBadStackError{})
+	assert.Equal(t, Group("error",
+		sf("message", "bad"),
+		sf("type", "logging.tBadStackError"),
+	), f)
+}
+
+func TestErrorCallers(t *testing.T) {
+	pcs := make([]uintptr, 2)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	assert.Equal(t, pcs, ErrorCallers(&tFieldError{pcs: pcs}))
+	assert.Equal(t, pcs, ErrorCallers(&tStackError{pcs: pcs}))
+	assert.Nil(t, ErrorCallers(tBadStackError{}))
+	assert.Nil(t, ErrorCallers(io.EOF))
+}
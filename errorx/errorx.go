@@ -0,0 +1,86 @@
+// Package errorx provides an error type that carries logging Fields, so that low-level
+// functions could attach context to the returned errors instead of logging them,
+// and the top-level logs all of them at once via logging.Error.
+package errorx
+
+import (
+	"fmt"
+
+	"github.com/yimi-go/logging"
+)
+
+// fieldError is an error that carries Fields and may wrap a cause.
+type fieldError struct {
+	cause  error
+	msg    string
+	fields []logging.Field
+}
+
+func (e *fieldError) Error() string {
+	if e.msg == "" && e.cause != nil {
+		return e.cause.Error()
+	}
+	return e.msg
+}
+
+func (e *fieldError) Unwrap() error { return e.cause }
+
+func (e *fieldError) LogFields() []logging.Field { return e.fields }
+
+// New returns an error with text and the given Fields.
+func New(text string, field ...logging.Field) error {
+	return &fieldError{msg: text, fields: copyFields(field)}
+}
+
+// Errorf formats according to a format specifier and returns the string as an error,
+// as fmt.Errorf does, including wrapping the operands of %w.
+//
+// Arguments that are logging.Field are not formatted, but carried by the returned error.
+// For example:
+//
+//	errorx.Errorf("load order %d: %w", orderID, err, logging.Int64("user_id", userID))
+func Errorf(format string, args ...any) error {
+	var fields []logging.Field
+	formatArgs := make([]any, 0, len(args))
+	for _, arg := range args {
+		if f, ok := arg.(logging.Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		formatArgs = append(formatArgs, arg)
+	}
+	return &fieldError{cause: fmt.Errorf(format, formatArgs...), fields: fields}
+}
+
+// Wrap returns an error wrapping cause, with message "text: cause" and the given Fields.
+// If cause is nil, Wrap returns nil.
+func Wrap(cause error, text string, field ...logging.Field) error {
+	if cause == nil {
+		return nil
+	}
+	return &fieldError{msg: text + ": " + cause.Error(), cause: cause, fields: copyFields(field)}
+}
+
+// With returns an error wrapping err, with the same message and the given Fields.
+// If err is nil, With returns nil.
+func With(err error, field ...logging.Field) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldError{cause: err, fields: copyFields(field)}
+}
+
+// Fields returns all the Fields carried by err and the errors it wraps, outermost first.
+// It is a shortcut of logging.ErrorFields.
+func Fields(err error) []logging.Field {
+	return logging.ErrorFields(err)
+}
+
+func copyFields(field []logging.Field) []logging.Field {
+	if len(field) == 0 {
+		return nil
+	}
+	fields := make([]logging.Field, len(field))
+	copy(fields, field)
+	return fields
+}
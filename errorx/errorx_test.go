@@ -0,0 +1,45 @@
+package errorx
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+)
+
+func TestNew(t *testing.T) {
+	fields := []logging.Field{logging.Int("user_id", 1)}
+	err := New("not found", fields...)
+	fields[0] = logging.Int("user_id", 2)
+	assert.EqualError(t, err, "not found")
+	assert.Nil(t, errors.Unwrap(err))
+	assert.Equal(t, []logging.Field{logging.Int("user_id", 1)}, Fields(err))
+}
+
+func TestErrorf(t *testing.T) {
+	err := Errorf("load order %d: %w", 2, io.EOF, logging.Int("user_id", 1), logging.Int("order_id", 2))
+	assert.EqualError(t, err, "load order 2: EOF")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []logging.Field{logging.Int("user_id", 1), logging.Int("order_id", 2)}, Fields(err))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil, "read", logging.Int("user_id", 1)))
+	inner := New("not found", logging.Int("order_id", 2))
+	err := Wrap(inner, "load user", logging.Int("user_id", 1))
+	assert.EqualError(t, err, "load user: not found")
+	assert.ErrorIs(t, err, inner)
+	assert.Equal(t, []logging.Field{logging.Int("user_id", 1), logging.Int("order_id", 2)}, Fields(err))
+}
+
+func TestWith(t *testing.T) {
+	assert.Nil(t, With(nil, logging.Int("user_id", 1)))
+	err := With(io.EOF, logging.Int("user_id", 1))
+	assert.EqualError(t, err, "EOF")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Empty(t, With(io.EOF).(logging.ErrorFielder).LogFields())
+	assert.Equal(t, []logging.Field{logging.Int("user_id", 1)}, Fields(err))
+}
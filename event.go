@@ -0,0 +1,290 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// EventCallerSkip is the number of frames the Event methods add between the caller
+// and the logging method of the Logger, see NewEvent.
+const EventCallerSkip = 2
+
+// maxPooledEventFields limits the capacity of Events put back to the pool,
+// so that an occasionally huge Event would not be retained forever.
+const maxPooledEventFields = 256
+
+// EventLogger is an optional interface that a vendor Logger could implement to
+// provide Events itself, e.g. to reuse a Logger with EventCallerSkip added.
+//
+// The Events got via NewEvent pass Fields backed by the pooled storage of the Events
+// to the logging methods, so that building and outputting them does not allocate.
+// The Logger given to NewEvent must not retain the Field slices nor the Fields
+// after its logging methods return; it should copy them, e.g. via AttrOf, if needed.
+type EventLogger interface {
+	// At returns an Event at lvl, or nil if lvl is not enabled.
+	At(lvl Level) *Event
+}
+
+// Event is a log being built by a fluent API, for hot paths:
+//
+//	logging.At(logger, logging.InfoLevel).Str("k", "v").Int("n", 1).Msg("done")
+//
+// A nil *Event is a disabled Event, all its methods do nothing.
+// An Event must not be used after Msg, Msgf or Send is called.
+type Event struct {
+	logger Logger
+	// origin and skipped cache the last Logger passed to At and it with EventCallerSkip added,
+	// so that the Events reused from the pool do not add the caller skip again.
+	origin  Logger
+	skipped Logger
+	attrs   []Attr
+	fields  []Field
+	level   Level
+	// owned reports whether the Fields output must be copied,
+	// i.e. the Event is not got via NewEvent, see EventLogger.
+	owned bool
+}
+
+var eventPool = sync.Pool{
+	New: func() any {
+		return &Event{attrs: make([]Attr, 0, 16), fields: make([]Field, 0, 16)}
+	},
+}
+
+// At returns an Event at lvl, which outputs via logger when Msg is called.
+// It returns nil, a disabled Event, if lvl is not enabled.
+//
+// If logger implements EventLogger, its At method is used.
+// Otherwise, the Event outputs via the logging method of lvl with Fields,
+// e.g. Infow for InfoLevel.
+func At(logger Logger, lvl Level) *Event {
+	if el, ok := logger.(EventLogger); ok {
+		return el.At(lvl)
+	}
+	if !logger.Enabled(lvl) {
+		return nil
+	}
+	e := eventPool.Get().(*Event)
+	if e.origin == nil || !sameLogger(e.origin, logger) {
+		e.origin, e.skipped = logger, AddCallerSkip(logger, EventCallerSkip)
+	}
+	e.logger = e.skipped
+	e.level = lvl
+	e.owned = true
+	return e
+}
+
+// sameLogger reports whether a and b are the same pointer Logger.
+// Loggers of the other kinds are not compared, as comparing them may panic.
+func sameLogger(a, b Logger) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Kind() == reflect.Pointer && a == b
+}
+
+// NewEvent gets an Event at lvl from the pool, which outputs via logger
+// without checking whether lvl is enabled. It is for vendors implementing EventLogger.
+//
+// The logger should have EventCallerSkip added, so that it reports the callers of the Event methods,
+// and must not retain the Fields passed to its logging methods, see EventLogger.
+func NewEvent(logger Logger, lvl Level) *Event {
+	e := eventPool.Get().(*Event)
+	e.logger = logger
+	e.level = lvl
+	return e
+}
+
+// Field adds the given Fields to the Event.
+func (e *Event) Field(field ...Field) *Event {
+	if e == nil {
+		return e
+	}
+	for _, f := range field {
+		e.attrs = append(e.attrs, AttrOf(f))
+	}
+	return e
+}
+
+// Str adds a Field of StringType to the Event.
+func (e *Event) Str(key, value string) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, String(key, value))
+	return e
+}
+
+// Int adds a Field of Int64Type to the Event.
+func (e *Event) Int(key string, value int) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Int(key, value))
+	return e
+}
+
+// Int64 adds a Field of Int64Type to the Event.
+func (e *Event) Int64(key string, value int64) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Int64(key, value))
+	return e
+}
+
+// Uint64 adds a Field of Uint64Type to the Event.
+func (e *Event) Uint64(key string, value uint64) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Uint64(key, value))
+	return e
+}
+
+// Float64 adds a Field of Float64Type to the Event.
+func (e *Event) Float64(key string, value float64) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Float64(key, value))
+	return e
+}
+
+// Bool adds a Field of BoolType to the Event.
+func (e *Event) Bool(key string, value bool) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Bool(key, value))
+	return e
+}
+
+// Dur adds a Field of DurationType to the Event.
+func (e *Event) Dur(key string, value time.Duration) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Duration(key, value))
+	return e
+}
+
+// Time adds a Field of TimeType to the Event.
+func (e *Event) Time(key string, value time.Time) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Time(key, value))
+	return e
+}
+
+// Bytes adds a Field of BinaryType to the Event.
+func (e *Event) Bytes(key string, value []byte) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Binary(key, value))
+	return e
+}
+
+// Stringer adds a Field of StringerType to the Event.
+func (e *Event) Stringer(key string, value fmt.Stringer) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Stringer(key, value))
+	return e
+}
+
+// Err adds a Field of ErrorType with key "error" to the Event.
+func (e *Event) Err(err error) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Error(err))
+	return e
+}
+
+// Any adds a Field of UnknownType to the Event.
+func (e *Event) Any(key string, value any) *Event {
+	if e == nil {
+		return e
+	}
+	e.attrs = append(e.attrs, Any(key, value))
+	return e
+}
+
+// Enabled reports whether the Event is enabled, i.e. not nil.
+func (e *Event) Enabled() bool {
+	return e != nil
+}
+
+// Msg outputs the Event with message, and puts the Event back to the pool.
+func (e *Event) Msg(message string) {
+	if e == nil {
+		return
+	}
+	e.output(message)
+}
+
+// Msgf outputs the Event with message formatted like fmt.Sprintf,
+// and puts the Event back to the pool.
+// Formatting is skipped if the Event is disabled.
+func (e *Event) Msgf(format string, v ...any) {
+	if e == nil {
+		return
+	}
+	e.output(fmt.Sprintf(format, v...))
+}
+
+// Send outputs the Event with an empty message, and puts the Event back to the pool.
+func (e *Event) Send() {
+	if e == nil {
+		return
+	}
+	e.output("")
+}
+
+func (e *Event) output(message string) {
+	var fields []Field
+	if e.owned {
+		// The Logger is not an EventLogger, and may retain the Fields.
+		fields = make([]Field, len(e.attrs))
+		for i := range e.attrs {
+			fields[i] = e.attrs[i]
+		}
+	} else {
+		for i := range e.attrs {
+			e.fields = append(e.fields, &e.attrs[i])
+		}
+		fields = e.fields
+	}
+	switch e.level {
+	case DebugLevel:
+		e.logger.Debugw(message, fields...)
+	case InfoLevel:
+		e.logger.Infow(message, fields...)
+	case WarnLevel:
+		e.logger.Warnw(message, fields...)
+	case ErrorLevel:
+		e.logger.Errorw(message, fields...)
+	}
+	e.release()
+}
+
+func (e *Event) release() {
+	if cap(e.attrs) > maxPooledEventFields {
+		return
+	}
+	for i := range e.attrs {
+		e.attrs[i] = Attr{}
+	}
+	e.attrs = e.attrs[:0]
+	for i := range e.fields {
+		e.fields[i] = nil
+	}
+	e.fields = e.fields[:0]
+	e.logger = nil
+	e.owned = false
+	eventPool.Put(e)
+}
@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tEntry struct {
+	caller Caller
+	msg    string
+	fields []Field
+	lvl    Level
+}
+
+type tel struct {
+	nopLogger
+	entries *[]tEntry
+	fields  []Field
+	skip    int
+	lvl     Level
+}
+
+func (t *tel) Enabled(lvl Level) bool { return t.lvl.Enabled(lvl) }
+func (t *tel) record(lvl Level, msg string, fields []Field) {
+	entry := tEntry{
+		caller: CaptureCaller(2 + t.skip),
+		msg:    msg,
+		fields: append([]Field(nil), t.fields...),
+		lvl:    lvl,
+	}
+	// The Fields of the Events got via NewEvent must not be retained.
+	for _, f := range fields {
+		entry.fields = append(entry.fields, AttrOf(f))
+	}
+	*t.entries = append(*t.entries, entry)
+}
+func (t *tel) Debugw(msg string, field ...Field) { t.record(DebugLevel, msg, field) }
+func (t *tel) Infow(msg string, field ...Field)  { t.record(InfoLevel, msg, field) }
+func (t *tel) Warnw(msg string, field ...Field)  { t.record(WarnLevel, msg, field) }
+func (t *tel) Errorw(msg string, field ...Field) { t.record(ErrorLevel, msg, field) }
+func (t *tel) WithField(field ...Field) Logger {
+	return &tel{entries: t.entries, fields: append(append([]Field(nil), t.fields...), field...), skip: t.skip, lvl: t.lvl}
+}
+func (t *tel) WithCallerSkip(skip int) Logger {
+	return &tel{entries: t.entries, fields: t.fields, skip: t.skip + skip, lvl: t.lvl}
+}
+
+type tEventLogger struct {
+	tel
+}
+
+func (t *tEventLogger) At(lvl Level) *Event {
+	return NewEvent(t.WithCallerSkip(EventCallerSkip), lvl)
+}
+
+func TestAt(t *testing.T) {
+	var entries []tEntry
+	l := &tel{entries: &entries, lvl: InfoLevel}
+	now := time.Now()
+
+	At(l, DebugLevel).Str("k", "v").Msg("debug")
+	assert.Empty(t, entries)
+
+	At(l, InfoLevel).
+		Str("str", "v").
+		Int("int", 1).
+		Int64("int64", 2).
+		Uint64("uint64", 3).
+		Float64("float64", 4).
+		Bool("bool", true).
+		Dur("dur", time.Second).
+		Time("time", now).
+		Bytes("bytes", []byte("b")).
+		Stringer("stringer", hanBoolStringer(true)).
+		Err(io.EOF).
+		Any("any", 5).
+		Field(sf("f", "v")).
+		Msg("info")
+	At(l, WarnLevel).Msgf("warn %d", 1)
+	At(l, ErrorLevel).Send()
+	At(l, DebugLevel).Send()
+
+	assert.Len(t, entries, 3)
+	assert.Equal(t, []Field{
+		String("str", "v"),
+		Int("int", 1),
+		Int64("int64", 2),
+		Uint64("uint64", 3),
+		Float64("float64", 4),
+		Bool("bool", true),
+		Duration("dur", time.Second),
+		Time("time", now),
+		Binary("bytes", []byte("b")),
+		Stringer("stringer", hanBoolStringer(true)),
+		Error(io.EOF),
+		Any("any", 5),
+		sf("f", "v"),
+	}, entries[0].fields)
+	assert.Equal(t, "info", entries[0].msg)
+	assert.Equal(t, InfoLevel, entries[0].lvl)
+	assert.Equal(t, tEntry{caller: entries[1].caller, msg: "warn 1", lvl: WarnLevel}, entries[1])
+	assert.Equal(t, tEntry{caller: entries[2].caller, lvl: ErrorLevel}, entries[2])
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging.TestAt", e.caller.Function)
+	}
+}
+
+func TestAt_debug(t *testing.T) {
+	var entries []tEntry
+	l := &tel{entries: &entries, lvl: DebugLevel}
+	At(l, DebugLevel).Int("n", 1).Msg("debug")
+	assert.Equal(t, []tEntry{{caller: entries[0].caller, msg: "debug", fields: []Field{Int("n", 1)}, lvl: DebugLevel}}, entries)
+}
+
+func TestAt_eventLogger(t *testing.T) {
+	var entries []tEntry
+	l := &tEventLogger{tel{entries: &entries, lvl: DebugLevel}}
+	At(l, InfoLevel).Msg("info")
+	At(l, InfoLevel).Str("k", "v").Field(sf("f", "v")).Msg("fields")
+	At(l, InfoLevel).Int("n", 1).Msg("reused")
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "github.com/yimi-go/logging.TestAt_eventLogger", entries[0].caller.Function)
+	assert.Equal(t, []Field{String("k", "v"), sf("f", "v")}, entries[1].fields)
+	assert.Equal(t, []Field{Int("n", 1)}, entries[2].fields)
+}
+
+// tFastLogger is an enabled Logger which does nothing and does not allocate,
+// but adding caller skips to it allocates.
+type tFastLogger struct {
+	nopLogger
+	skip int
+}
+
+func (t *tFastLogger) Enabled(Level) bool { return true }
+func (t *tFastLogger) WithCallerSkip(skip int) Logger {
+	return &tFastLogger{skip: t.skip + skip}
+}
+
+type tFastEventLogger struct {
+	tFastLogger
+	skipped Logger
+}
+
+func (t *tFastEventLogger) At(lvl Level) *Event {
+	return NewEvent(t.skipped, lvl)
+}
+
+func TestEvent_enabledAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items randomly with the race detector")
+	}
+	el := &tFastEventLogger{}
+	el.skipped = el.WithCallerSkip(EventCallerSkip)
+	allocs := testing.AllocsPerRun(100, func() {
+		At(el, InfoLevel).Str("k", "v").Int("n", 1).Bool("b", true).Dur("d", time.Second).Msg("")
+	})
+	assert.Equal(t, float64(0), allocs)
+
+	l := &tFastLogger{}
+	allocs = testing.AllocsPerRun(100, func() {
+		At(l, InfoLevel).Str("k", "v").Int("n", 1).Msg("")
+	})
+	// The Fields are copied for the Loggers other than EventLoggers,
+	// the Field slice and the 2 Fields, while the caller skip is added once.
+	assert.Equal(t, float64(3), allocs)
+}
+
+func TestEvent_uncomparableLogger(t *testing.T) {
+	var entries []tEntry
+	l := tUncomparableLogger{tel: &tel{entries: &entries, lvl: InfoLevel}}
+	At(l, InfoLevel).Msg("a")
+	At(l, InfoLevel).Msg("b")
+	assert.Len(t, entries, 2)
+}
+
+// tUncomparableLogger panics if compared by ==.
+type tUncomparableLogger struct {
+	*tel
+	_ []int
+}
+
+func TestEvent_disabled(t *testing.T) {
+	var e *Event
+	assert.False(t, e.Enabled())
+	assert.True(t, At(&tel{entries: &[]tEntry{}, lvl: InfoLevel}, InfoLevel).Enabled())
+	allocs := testing.AllocsPerRun(100, func() {
+		e := At(&nopLogger{}, InfoLevel)
+		e.Str("k", "v").Int("n", 1).Bool("b", true).Dur("d", time.Second).Err(io.EOF).Any("a", 1)
+		e.Msgf("%d", 1)
+		e.Send()
+		e.Msg("")
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestEvent_release(t *testing.T) {
+	var entries []tEntry
+	l := &tel{entries: &entries, lvl: InfoLevel}
+	e := At(l, InfoLevel)
+	for i := 0; i <= maxPooledEventFields; i++ {
+		e.Int("n", i)
+	}
+	e.Msg("big")
+	assert.Len(t, entries[0].fields, maxPooledEventFields+1)
+	assert.Equal(t, Int("n", 1), entries[0].fields[1])
+}
@@ -3,6 +3,8 @@ package logging
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 )
 
@@ -51,8 +53,18 @@ const (
 	StringerType
 	// ErrorType indicates that the field carries an error.
 	ErrorType
-	// StackType indicates that the field captures stacktrace of the current goroutine.
+	// StackType indicates that the field carries a *Stacktrace of the current goroutine,
+	// which is captured when the field is created.
 	StackType
+	// GroupType indicates that the field carries a []Field which should be nested under the field key.
+	GroupType
+	// NamespaceType indicates that the field carries nothing, but all the subsequent fields
+	// should be nested under the field key.
+	NamespaceType
+	// LazyType indicates that the field carries a func() Field, which should be called
+	// to get the actual Field only when the log is really going to be output.
+	// Use Resolve to get the actual Field.
+	LazyType
 )
 
 // Field is logging field.
@@ -65,35 +77,194 @@ type Field interface {
 	Value() any
 }
 
-type field struct {
-	val any
-	key string
-	typ FieldType
+// Attr is the Field implementation returned by the constructors of this package.
+//
+// Attr is a concrete struct, so that creating Fields of the common types, e.g. Int, String,
+// Bool, Duration and Time, does not allocate by itself. Storing an Attr as a Field boxes it,
+// which allocates once per Field, so logging via the w methods of Logger allocates for every
+// Field as well as for the Field slice escaping to the vendor. Logs could be output without
+// allocations only via Event with Loggers implementing EventLogger, see At.
+// The zero Attr is a Field of UnknownType with an empty key and a nil value.
+//
+// Vendors should read the values via the typed accessors of AttrOf or Visit rather than Value,
+// which boxes the value into an interface and may allocate.
+type Attr struct {
+	iface any
+	key   string
+	str   string
+	num   int64
+	typ   FieldType
+}
+
+// AttrOf returns f as an Attr, whose values could be read via the typed accessors.
+// Fields implemented outside this package are converted by their Types and Values,
+// and a nil Field results in the zero Attr.
+func AttrOf(f Field) Attr {
+	switch a := f.(type) {
+	case Attr:
+		return a
+	case *Attr:
+		if a == nil {
+			return Attr{}
+		}
+		return *a
+	case nil:
+		return Attr{}
+	}
+	key, value := f.Key(), f.Value()
+	switch v := value.(type) {
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case float64:
+		return Float64(key, v)
+	case float32:
+		return Float32(key, v)
+	case int64:
+		return Int64(key, v)
+	case int32:
+		return Int32(key, v)
+	case int16:
+		return Int16(key, v)
+	case int8:
+		return Int8(key, v)
+	case string:
+		if f.Type() == StringType {
+			return String(key, v)
+		}
+	case time.Time:
+		return Time(key, v)
+	case uint64:
+		return Uint64(key, v)
+	case uint32:
+		return Uint32(key, v)
+	case uint16:
+		return Uint16(key, v)
+	case uint8:
+		return Uint8(key, v)
+	case uintptr:
+		return Uintptr(key, v)
+	}
+	return Attr{key: key, typ: f.Type(), iface: value}
+}
+
+// Key is field key.
+func (f Attr) Key() string { return f.key }
+
+// Type is field type.
+func (f Attr) Type() FieldType { return f.typ }
+
+// Value is field value, of the Go type matching the field type,
+// e.g. int64 for Int64Type and time.Time for TimeType.
+func (f Attr) Value() any {
+	switch f.typ {
+	case BoolType:
+		return f.num == 1
+	case DurationType:
+		return time.Duration(f.num)
+	case Float64Type:
+		return math.Float64frombits(uint64(f.num))
+	case Float32Type:
+		return math.Float32frombits(uint32(f.num))
+	case Int64Type:
+		return f.num
+	case Int32Type:
+		return int32(f.num)
+	case Int16Type:
+		return int16(f.num)
+	case Int8Type:
+		return int8(f.num)
+	case StringType:
+		return f.str
+	case TimeType:
+		return f.TimeValue()
+	case Uint64Type:
+		return uint64(f.num)
+	case Uint32Type:
+		return uint32(f.num)
+	case Uint16Type:
+		return uint16(f.num)
+	case Uint8Type:
+		return uint8(f.num)
+	case UintptrType:
+		return uintptr(f.num)
+	default:
+		return f.iface
+	}
 }
 
-func (f field) Key() string     { return f.key }
-func (f field) Type() FieldType { return f.typ }
-func (f field) Value() any      { return f.val }
+// BoolValue returns the value of a Field of BoolType.
+func (f Attr) BoolValue() bool { return f.num == 1 }
+
+// DurationValue returns the value of a Field of DurationType.
+func (f Attr) DurationValue() time.Duration { return time.Duration(f.num) }
+
+// Float64Value returns the value of a Field of Float64Type or Float32Type.
+func (f Attr) Float64Value() float64 {
+	if f.typ == Float32Type {
+		return float64(math.Float32frombits(uint32(f.num)))
+	}
+	return math.Float64frombits(uint64(f.num))
+}
+
+// Int64Value returns the value of a Field of Int64Type, Int32Type, Int16Type or Int8Type.
+func (f Attr) Int64Value() int64 { return f.num }
+
+// Uint64Value returns the value of a Field of Uint64Type, Uint32Type, Uint16Type, Uint8Type or UintptrType.
+func (f Attr) Uint64Value() uint64 { return uint64(f.num) }
+
+// StringValue returns the value of a Field of StringType.
+func (f Attr) StringValue() string { return f.str }
+
+// TimeValue returns the value of a Field of TimeType.
+func (f Attr) TimeValue() time.Time {
+	switch v := f.iface.(type) {
+	case time.Time:
+		return v
+	case *time.Location:
+		return time.Unix(0, f.num).In(v)
+	default:
+		return time.Unix(0, f.num)
+	}
+}
+
+// Interface returns the value of a Field of the other types, which is stored as an interface.
+func (f Attr) Interface() any { return f.iface }
+
+var (
+	// times out of this range can not be represented by nanoseconds in int64.
+	minTimeInt64 = time.Unix(0, math.MinInt64)
+	maxTimeInt64 = time.Unix(0, math.MaxInt64)
+)
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
 
 // Any creates a Field of UnknownType value.
-func Any(key string, value any) Field {
-	return field{key: key, typ: UnknownType, val: value}
+// Values of types registered via RegisterEncoder are encoded when the Field is rendered.
+func Any(key string, value any) Attr {
+	return Attr{key: key, typ: UnknownType, iface: value}
 }
 
 // Binary creates a Field of BinaryType value.
-func Binary(key string, value []byte) Field {
+func Binary(key string, value []byte) Attr {
 	val := make([]byte, len(value))
 	copy(val, value)
-	return field{key: key, typ: BinaryType, val: val}
+	return Attr{key: key, typ: BinaryType, iface: val}
 }
 
 // Bool creates a Field of BoolType value.
-func Bool(key string, value bool) Field {
-	return field{key: key, typ: BoolType, val: value}
+func Bool(key string, value bool) Attr {
+	return Attr{key: key, typ: BoolType, num: boolToInt64(value)}
 }
 
 // Boolp creates a Field of BoolType value if value is not nil, or a Field of UnknownType value if value is nil.
-func Boolp(key string, value *bool) Field {
+func Boolp(key string, value *bool) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -101,13 +272,13 @@ func Boolp(key string, value *bool) Field {
 }
 
 // Complex128 creates a Field of Complex128Type value.
-func Complex128(key string, value complex128) Field {
-	return field{key: key, typ: Complex128Type, val: value}
+func Complex128(key string, value complex128) Attr {
+	return Attr{key: key, typ: Complex128Type, iface: value}
 }
 
 // Complex128p creates a Field of Complex128Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Complex128p(key string, value *complex128) Field {
+func Complex128p(key string, value *complex128) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -115,13 +286,13 @@ func Complex128p(key string, value *complex128) Field {
 }
 
 // Complex64 creates a Field of Complex64Type value.
-func Complex64(key string, value complex64) Field {
-	return field{key: key, typ: Complex64Type, val: value}
+func Complex64(key string, value complex64) Attr {
+	return Attr{key: key, typ: Complex64Type, iface: value}
 }
 
 // Complex64p creates a Field of Complex64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Complex64p(key string, value *complex64) Field {
+func Complex64p(key string, value *complex64) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -129,13 +300,13 @@ func Complex64p(key string, value *complex64) Field {
 }
 
 // Duration creates a Field of DurationType value.
-func Duration(key string, value time.Duration) Field {
-	return field{key: key, typ: DurationType, val: value}
+func Duration(key string, value time.Duration) Attr {
+	return Attr{key: key, typ: DurationType, num: int64(value)}
 }
 
 // Durationp creates a Field of DurationType value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Durationp(key string, value *time.Duration) Field {
+func Durationp(key string, value *time.Duration) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -143,13 +314,13 @@ func Durationp(key string, value *time.Duration) Field {
 }
 
 // Float64 creates a Field of Float64Type value.
-func Float64(key string, value float64) Field {
-	return field{key: key, typ: Float64Type, val: value}
+func Float64(key string, value float64) Attr {
+	return Attr{key: key, typ: Float64Type, num: int64(math.Float64bits(value))}
 }
 
 // Float64p creates a Field of Float64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Float64p(key string, value *float64) Field {
+func Float64p(key string, value *float64) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -157,13 +328,13 @@ func Float64p(key string, value *float64) Field {
 }
 
 // Float32 creates a Field of Float32Type value.
-func Float32(key string, value float32) Field {
-	return field{key: key, typ: Float32Type, val: value}
+func Float32(key string, value float32) Attr {
+	return Attr{key: key, typ: Float32Type, num: int64(math.Float32bits(value))}
 }
 
 // Float32p creates a Field of Float32Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Float32p(key string, value *float32) Field {
+func Float32p(key string, value *float32) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -171,13 +342,13 @@ func Float32p(key string, value *float32) Field {
 }
 
 // Int creates a Field of Int64Type value.
-func Int(key string, value int) Field {
-	return field{key: key, typ: Int64Type, val: int64(value)}
+func Int(key string, value int) Attr {
+	return Attr{key: key, typ: Int64Type, num: int64(value)}
 }
 
 // Intp creates a Field of Int64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Intp(key string, value *int) Field {
+func Intp(key string, value *int) Attr {
 	if value == nil {
 		return Any(key, (*int64)(nil))
 	}
@@ -185,13 +356,13 @@ func Intp(key string, value *int) Field {
 }
 
 // Int64 creates a Field of Int64Type value.
-func Int64(key string, value int64) Field {
-	return field{key: key, typ: Int64Type, val: value}
+func Int64(key string, value int64) Attr {
+	return Attr{key: key, typ: Int64Type, num: value}
 }
 
 // Int64p creates a Field of Int64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Int64p(key string, value *int64) Field {
+func Int64p(key string, value *int64) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -199,13 +370,13 @@ func Int64p(key string, value *int64) Field {
 }
 
 // Int32 creates a Field of Int32Type value.
-func Int32(key string, value int32) Field {
-	return field{key: key, typ: Int32Type, val: value}
+func Int32(key string, value int32) Attr {
+	return Attr{key: key, typ: Int32Type, num: int64(value)}
 }
 
 // Int32p creates a Field of Int32Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Int32p(key string, value *int32) Field {
+func Int32p(key string, value *int32) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -213,13 +384,13 @@ func Int32p(key string, value *int32) Field {
 }
 
 // Int16 creates a Field of Int16Type value.
-func Int16(key string, value int16) Field {
-	return field{key: key, typ: Int16Type, val: value}
+func Int16(key string, value int16) Attr {
+	return Attr{key: key, typ: Int16Type, num: int64(value)}
 }
 
 // Int16p creates a Field of Int16Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Int16p(key string, value *int16) Field {
+func Int16p(key string, value *int16) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -227,13 +398,13 @@ func Int16p(key string, value *int16) Field {
 }
 
 // Int8 creates a Field of Int8Type value.
-func Int8(key string, value int8) Field {
-	return field{key: key, typ: Int8Type, val: value}
+func Int8(key string, value int8) Attr {
+	return Attr{key: key, typ: Int8Type, num: int64(value)}
 }
 
 // Int8p creates a Field of Int8Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Int8p(key string, value *int8) Field {
+func Int8p(key string, value *int8) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -241,13 +412,13 @@ func Int8p(key string, value *int8) Field {
 }
 
 // String creates a Field of StringType value.
-func String(key string, value string) Field {
-	return field{key: key, typ: StringType, val: value}
+func String(key string, value string) Attr {
+	return Attr{key: key, typ: StringType, str: value}
 }
 
 // Stringp creates a Field of StringType value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Stringp(key string, value *string) Field {
+func Stringp(key string, value *string) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -255,13 +426,16 @@ func Stringp(key string, value *string) Field {
 }
 
 // Time creates a Field of TimeType value.
-func Time(key string, value time.Time) Field {
-	return field{key: key, typ: TimeType, val: value}
+func Time(key string, value time.Time) Attr {
+	if value.Before(minTimeInt64) || value.After(maxTimeInt64) {
+		return Attr{key: key, typ: TimeType, iface: value}
+	}
+	return Attr{key: key, typ: TimeType, num: value.UnixNano(), iface: value.Location()}
 }
 
 // Timep creates a Field of TimeType value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Timep(key string, value *time.Time) Field {
+func Timep(key string, value *time.Time) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -269,13 +443,13 @@ func Timep(key string, value *time.Time) Field {
 }
 
 // Uint creates a Field of Uint64Type value.
-func Uint(key string, value uint) Field {
-	return field{key: key, typ: Uint64Type, val: uint64(value)}
+func Uint(key string, value uint) Attr {
+	return Attr{key: key, typ: Uint64Type, num: int64(value)}
 }
 
 // Uintp creates a Field of Uint64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uintp(key string, value *uint) Field {
+func Uintp(key string, value *uint) Attr {
 	if value == nil {
 		return Any(key, (*uint64)(nil))
 	}
@@ -283,13 +457,13 @@ func Uintp(key string, value *uint) Field {
 }
 
 // Uint64 creates a Field of Uint64Type value.
-func Uint64(key string, value uint64) Field {
-	return field{key: key, typ: Uint64Type, val: value}
+func Uint64(key string, value uint64) Attr {
+	return Attr{key: key, typ: Uint64Type, num: int64(value)}
 }
 
 // Uint64p creates a Field of Uint64Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uint64p(key string, value *uint64) Field {
+func Uint64p(key string, value *uint64) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -297,13 +471,13 @@ func Uint64p(key string, value *uint64) Field {
 }
 
 // Uint32 creates a Field of Uint32Type value.
-func Uint32(key string, value uint32) Field {
-	return field{key: key, typ: Uint32Type, val: value}
+func Uint32(key string, value uint32) Attr {
+	return Attr{key: key, typ: Uint32Type, num: int64(value)}
 }
 
 // Uint32p creates a Field of Uint32Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uint32p(key string, value *uint32) Field {
+func Uint32p(key string, value *uint32) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -311,13 +485,13 @@ func Uint32p(key string, value *uint32) Field {
 }
 
 // Uint16 creates a Field of Uint16Type value.
-func Uint16(key string, value uint16) Field {
-	return field{key: key, typ: Uint16Type, val: value}
+func Uint16(key string, value uint16) Attr {
+	return Attr{key: key, typ: Uint16Type, num: int64(value)}
 }
 
 // Uint16p creates a Field of Uint16Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uint16p(key string, value *uint16) Field {
+func Uint16p(key string, value *uint16) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -325,13 +499,13 @@ func Uint16p(key string, value *uint16) Field {
 }
 
 // Uint8 creates a Field of Uint8Type value.
-func Uint8(key string, value uint8) Field {
-	return field{key: key, typ: Uint8Type, val: value}
+func Uint8(key string, value uint8) Attr {
+	return Attr{key: key, typ: Uint8Type, num: int64(value)}
 }
 
 // Uint8p creates a Field of Uint8Type value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uint8p(key string, value *uint8) Field {
+func Uint8p(key string, value *uint8) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -339,13 +513,13 @@ func Uint8p(key string, value *uint8) Field {
 }
 
 // Uintptr creates a Field of UintptrType value.
-func Uintptr(key string, value uintptr) Field {
-	return field{key: key, typ: UintptrType, val: value}
+func Uintptr(key string, value uintptr) Attr {
+	return Attr{key: key, typ: UintptrType, num: int64(value)}
 }
 
 // Uintptrp creates a Field of UintptrType value if value is not nil,
 // or a Field of UnknownType value if value is nil.
-func Uintptrp(key string, value *uintptr) Field {
+func Uintptrp(key string, value *uintptr) Attr {
 	if value == nil {
 		return Any(key, value)
 	}
@@ -353,49 +527,242 @@ func Uintptrp(key string, value *uintptr) Field {
 }
 
 // Stringer creates a Field of StringerType value.
-func Stringer(key string, value fmt.Stringer) Field {
-	return field{key: key, typ: StringerType, val: value}
+func Stringer(key string, value fmt.Stringer) Attr {
+	return Attr{key: key, typ: StringerType, iface: value}
 }
 
 // Error creates a Field of ErrorType value, with key "error".
-func Error(err error) Field {
-	return field{key: "error", typ: ErrorType, val: err}
+//
+// Vendors are expected to output the Fields carried by err and the errors it wraps
+// along with it, see ErrorFielder and ErrorFields.
+func Error(err error) Attr {
+	return Attr{key: "error", typ: ErrorType, iface: err}
 }
 
 // NamedError create a Field of ErrorType value.
-func NamedError(key string, err error) Field {
-	return field{key: key, typ: ErrorType, val: err}
+// The Fields carried by err are expected to be output along with it, as Error does.
+func NamedError(key string, err error) Attr {
+	return Attr{key: key, typ: ErrorType, iface: err}
 }
 
-// Stack create a Field that captures stacktrace of the current goroutine.
-func Stack(key string) Field {
-	return field{key: key, typ: StackType, val: 0}
+// Stack create a Field that captures stacktrace of the current goroutine,
+// starting from the caller of Stack.
+func Stack(key string) Attr {
+	return Attr{key: key, typ: StackType, iface: captureStack(1)}
 }
 
 // StackSkip create a Field that captures stacktrace of the current goroutine,
-// skipping the given number of frames from the top of the stacktrace.
-func StackSkip(key string, skip int) Field {
-	return field{key: key, typ: StackType, val: skip}
+// skipping the given number of frames above the caller of StackSkip.
+func StackSkip(key string, skip int) Attr {
+	if skip < 0 {
+		skip = 0
+	}
+	return Attr{key: key, typ: StackType, iface: captureStack(skip + 1)}
+}
+
+// Group creates a Field of GroupType value, which nests the given fields under key.
+//
+// For example, Group("http", String("method", "GET"), Int("status", 200)) would be
+// output as {"http":{"method":"GET","status":200}} by a JSON encoding vendor.
+func Group(key string, fields ...Field) Attr {
+	val := make([]Field, len(fields))
+	copy(val, fields)
+	return Attr{key: key, typ: GroupType, iface: val}
+}
+
+// Namespace creates a Field of NamespaceType value.
+// All the fields after it, including the ones added to the Logger later,
+// should be nested under key.
+func Namespace(key string) Attr {
+	return Attr{key: key, typ: NamespaceType}
+}
+
+// Lazy creates a Field of LazyType value, with which the value is computed by calling fn
+// only when the log is really going to be output.
+// fn is called at most once, even if the Field is output more than once.
+//
+// The computed value is wrapped into a Field with a FieldType inferred from its dynamic type,
+// for example, a string value results a Field of StringType, and an error value results
+// a Field of ErrorType. Values of other types result Fields of UnknownType.
+// A Field value keeps its FieldType and value but takes the key of the Lazy Field.
+func Lazy(key string, fn func() any) Attr {
+	return Attr{key: key, typ: LazyType, iface: lazyOnce(func() Field {
+		return inferField(key, fn())
+	})}
+}
+
+// LazyField creates a Field of LazyType value, with which the actual Field is created by
+// calling fn only when the log is really going to be output.
+// fn is called at most once, even if the Field is output more than once.
+//
+// Because the key is unknown before fn is called, Key method of the returned Field returns
+// an empty string. Use Resolve to get the actual Field.
+func LazyField(fn func() Field) Attr {
+	return Attr{typ: LazyType, iface: lazyOnce(fn)}
+}
+
+func lazyOnce(fn func() Field) func() Field {
+	var once sync.Once
+	var resolved Field
+	return func() Field {
+		once.Do(func() {
+			resolved = fn()
+		})
+		return resolved
+	}
+}
+
+// Resolve returns the actual Field of a Field of LazyType, by calling the deferred function.
+// Fields of other types are returned as they are.
+//
+// Vendors should resolve every Field of LazyType before encoding it.
+func Resolve(f Field) Attr {
+	a := AttrOf(f)
+	for a.typ == LazyType {
+		fn, ok := a.iface.(func() Field)
+		if !ok || fn == nil {
+			return Any(a.key, nil)
+		}
+		a = AttrOf(fn())
+	}
+	return a
+}
+
+// Rename returns a copy of f with the given key.
+func Rename(f Field, key string) Attr {
+	if f.Type() == LazyType {
+		return Attr{key: key, typ: LazyType, iface: lazyOnce(func() Field {
+			return Rename(Resolve(f), key)
+		})}
+	}
+	a := AttrOf(f)
+	a.key = key
+	return a
+}
+
+// Infer creates a Field with a FieldType inferred from the dynamic type of value,
+// the same as the ones resolved from Lazy, e.g. for bridging key-value pairs of other logging APIs.
+// If value is a Field, it is renamed to key.
+func Infer(key string, value any) Attr {
+	return inferField(key, value)
+}
+
+// inferField creates a Field with a FieldType inferred from the dynamic type of value.
+// The encoders registered via RegisterEncoder take precedence over error and fmt.Stringer.
+func inferField(key string, value any) Attr {
+	switch v := value.(type) {
+	case nil:
+		return Any(key, nil)
+	case Field:
+		return Rename(v, key)
+	case []byte:
+		return Binary(key, v)
+	case bool:
+		return Bool(key, v)
+	case complex128:
+		return Complex128(key, v)
+	case complex64:
+		return Complex64(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case float64:
+		return Float64(key, v)
+	case float32:
+		return Float32(key, v)
+	case int:
+		return Int(key, v)
+	case int64:
+		return Int64(key, v)
+	case int32:
+		return Int32(key, v)
+	case int16:
+		return Int16(key, v)
+	case int8:
+		return Int8(key, v)
+	case string:
+		return String(key, v)
+	case time.Time:
+		return Time(key, v)
+	case uint:
+		return Uint(key, v)
+	case uint64:
+		return Uint64(key, v)
+	case uint32:
+		return Uint32(key, v)
+	case uint16:
+		return Uint16(key, v)
+	case uint8:
+		return Uint8(key, v)
+	case uintptr:
+		return Uintptr(key, v)
+	}
+	if f := Encode(Any(key, value)); f.typ != UnknownType {
+		return f
+	}
+	switch v := value.(type) {
+	case error:
+		return NamedError(key, v)
+	case fmt.Stringer:
+		return Stringer(key, v)
+	default:
+		return Any(key, v)
+	}
 }
 
 type fieldKey struct{}
 
 // NewContext wraps fields into a new context and return it.
+// The fields are merged after the ones already in ctx by the MergePolicy set via SwapMergePolicy,
+// i.e. they replace the ones with the same keys by default.
+//
+// The Fields in contexts are immutable: the new context never shares memory with its parent,
+// so that contexts derived from the same parent concurrently never see each other's Fields.
 func NewContext(ctx context.Context, field ...Field) context.Context {
-	fields, _ := ctx.Value(fieldKey{}).([]Field)
-	fields = append(fields, field...)
-	return context.WithValue(ctx, fieldKey{}, fields)
+	set, ok := ctx.Value(fieldKey{}).(FieldSet)
+	if !ok {
+		set = FieldSet{policy: GetMergePolicy()}
+	}
+	return context.WithValue(ctx, fieldKey{}, set.With(field...))
+}
+
+// NewContextWithout returns a new context without the Fields with any of keys.
+// If ctx has no such Fields, ctx is returned.
+func NewContextWithout(ctx context.Context, keys ...string) context.Context {
+	set, ok := ctx.Value(fieldKey{}).(FieldSet)
+	if !ok {
+		return ctx
+	}
+	without := set.Without(keys...)
+	if without.Len() == set.Len() {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldKey{}, without)
+}
+
+// FieldsFromContext returns the FieldSet wrapped in ctx via NewContext.
+// An empty FieldSet of the MergePolicy set via SwapMergePolicy is returned if there is none.
+func FieldsFromContext(ctx context.Context) FieldSet {
+	set, ok := ctx.Value(fieldKey{}).(FieldSet)
+	if !ok {
+		return FieldSet{policy: GetMergePolicy()}
+	}
+	return set
 }
 
 // WithContextField tries extract fields from context and returns a Logger with these fields.
 // If no fields found, the origin logger is returned.
+//
+// The Fields are merged with each other by the MergePolicy of the context, but they are added
+// to logger via WithField, so they are not merged with the Fields logger already has, which
+// are unknown to this package. Duplicate keys across them are left to the vendor,
+// unless logger is wrapped via MergeLogger, which merges them by its own MergePolicy.
 func WithContextField(ctx context.Context, logger Logger) Logger {
-	fields, ok := ctx.Value(fieldKey{}).([]Field)
+	set, ok := ctx.Value(fieldKey{}).(FieldSet)
 	if !ok {
 		return logger
 	}
-	if len(fields) == 0 {
+	if set.Len() == 0 {
 		return logger
 	}
-	return logger.WithField(fields...)
+	return logger.WithField(set.Fields()...)
 }
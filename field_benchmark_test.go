@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sinkAttr   Attr
+	sinkFields = make([]Field, 1)
+)
+
+// infow logs via the Logger interface, so that the variadic Fields escape
+// as they do when calling real vendors.
+//
+//go:noinline
+func infow(logger Logger, message string, field ...Field) {
+	logger.Infow(message, field...)
+}
+
+func TestAttr_zeroAllocs(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		fn   func()
+		name string
+	}{
+		{name: "Int", fn: func() { sinkAttr = Int("key", 42) }},
+		{name: "Int64", fn: func() { sinkAttr = Int64("key", 42) }},
+		{name: "Uint64", fn: func() { sinkAttr = Uint64("key", 42) }},
+		{name: "Float64", fn: func() { sinkAttr = Float64("key", 4.2) }},
+		{name: "String", fn: func() { sinkAttr = String("key", "value") }},
+		{name: "Bool", fn: func() { sinkAttr = Bool("key", true) }},
+		{name: "Duration", fn: func() { sinkAttr = Duration("key", time.Second) }},
+		{name: "Time", fn: func() { sinkAttr = Time("key", now) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, float64(0), testing.AllocsPerRun(100, tt.fn))
+		})
+	}
+}
+
+func TestField_allocs(t *testing.T) {
+	// Storing an Attr as a Field boxes it, which allocates once per Field.
+	assert.Equal(t, float64(1), testing.AllocsPerRun(100, func() { sinkFields[0] = Int("key", 42) }))
+	assert.Equal(t, float64(1), testing.AllocsPerRun(100, func() { sinkFields[0] = String("key", "value") }))
+
+	// Logging via the w methods allocates the Field slice and boxes every Field.
+	l := &tFastLogger{}
+	allocs := testing.AllocsPerRun(100, func() {
+		infow(l, "msg", Int("n", 1), String("k", "v"))
+	})
+	assert.Equal(t, float64(3), allocs)
+}
+
+func BenchmarkInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = Int("key", i)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = String("key", "value")
+	}
+}
+
+func BenchmarkBool(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = Bool("key", i%2 == 0)
+	}
+}
+
+func BenchmarkDuration(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = Duration("key", time.Duration(i))
+	}
+}
+
+func BenchmarkTime(b *testing.B) {
+	now := time.Now()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = Time("key", now)
+	}
+}
+
+func BenchmarkAny(b *testing.B) {
+	v := map[string]int{"a": 1}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkFields[0] = Any("key", v)
+	}
+}
+
+func BenchmarkAttr(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkAttr = Int("key", i)
+	}
+}
+
+func BenchmarkInfow(b *testing.B) {
+	l := &tFastLogger{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		infow(l, "msg", String("k", "v"), Int("n", i))
+	}
+}
+
+func BenchmarkEvent(b *testing.B) {
+	el := &tFastEventLogger{}
+	el.skipped = el.WithCallerSkip(EventCallerSkip)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		At(el, InfoLevel).Str("k", "v").Int("n", i).Msg("msg")
+	}
+}
+
+func BenchmarkEvent_disabled(b *testing.B) {
+	logger := &tel{entries: &[]tEntry{}, lvl: InfoLevel}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		At(logger, DebugLevel).Str("k", "v").Int("n", i).Msg("disabled")
+	}
+}
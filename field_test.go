@@ -3,6 +3,7 @@ package logging
 import (
 	"context"
 	"io"
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -20,6 +21,17 @@ func (t *tl) WithField(field ...Field) Logger {
 	return &tl{field: append(t.field, field...)}
 }
 
+// tField is an unpacked Field for comparing.
+type tField struct {
+	val any
+	key string
+	typ FieldType
+}
+
+func unpack(f Field) tField {
+	return tField{f.Value(), f.Key(), f.Type()}
+}
+
 type hanBoolStringer bool
 
 func (h hanBoolStringer) String() string {
@@ -33,36 +45,36 @@ func (h hanBoolStringer) String() string {
 }
 
 func TestField_Key(t *testing.T) {
-	f := field{key: "abc"}
+	f := Attr{key: "abc"}
 	assert.Equal(t, "abc", f.Key())
 }
 
 func TestField_Type(t *testing.T) {
-	f := field{typ: StringType}
+	f := Attr{typ: StringType}
 	assert.Equal(t, StringType, f.Type())
 }
 
 func TestField_Value(t *testing.T) {
-	f := field{val: 123}
+	f := Attr{iface: 123}
 	assert.Equal(t, 123, f.Value())
 }
 
 func TestAny(t *testing.T) {
 	key, value := "key", map[string]any{"a": 1}
 	f := Any(key, value)
-	assert.Equal(t, field{value, key, UnknownType}, f)
+	assert.Equal(t, tField{value, key, UnknownType}, unpack(f))
 }
 
 func TestBinary(t *testing.T) {
 	key, value := "key", []byte{'a', 'b', 'c'}
 	f := Binary(key, value)
-	assert.Equal(t, field{value, key, BinaryType}, f)
+	assert.Equal(t, tField{value, key, BinaryType}, unpack(f))
 }
 
 func TestBool(t *testing.T) {
 	key := "key"
 	f := Bool(key, true)
-	assert.Equal(t, field{true, key, BoolType}, f)
+	assert.Equal(t, tField{true, key, BoolType}, unpack(f))
 }
 
 func TestBoolp(t *testing.T) {
@@ -73,23 +85,23 @@ func TestBoolp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*bool)(nil), "key", UnknownType},
+			want: tField{(*bool)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", BoolType},
+			want: tField{v, "key", BoolType},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Boolp(tt.args.key, tt.args.value), "Boolp(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Boolp(tt.args.key, tt.args.value)), "Boolp(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -97,7 +109,7 @@ func TestBoolp(t *testing.T) {
 func TestComplex128(t *testing.T) {
 	key := "key"
 	f := Complex128(key, 1+2i)
-	assert.Equal(t, field{1 + 2i, key, Complex128Type}, f)
+	assert.Equal(t, tField{1 + 2i, key, Complex128Type}, unpack(f))
 }
 
 func TestComplex128p(t *testing.T) {
@@ -108,18 +120,18 @@ func TestComplex128p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*complex128)(nil), "key", UnknownType},
+			want: tField{(*complex128)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Complex128Type},
+			want: tField{v, "key", Complex128Type},
 		},
 	}
 	for _, tt := range tests {
@@ -127,7 +139,7 @@ func TestComplex128p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Complex128p(tt.args.key, tt.args.value),
+				unpack(Complex128p(tt.args.key, tt.args.value)),
 				"Complex128p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -139,7 +151,7 @@ func TestComplex128p(t *testing.T) {
 func TestComplex64(t *testing.T) {
 	key := "key"
 	f := Complex64(key, (complex64)(1+2i))
-	assert.Equal(t, field{(complex64)(1 + 2i), key, Complex64Type}, f)
+	assert.Equal(t, tField{(complex64)(1 + 2i), key, Complex64Type}, unpack(f))
 }
 
 func TestComplex64p(t *testing.T) {
@@ -150,18 +162,18 @@ func TestComplex64p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*complex64)(nil), "key", UnknownType},
+			want: tField{(*complex64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Complex64Type},
+			want: tField{v, "key", Complex64Type},
 		},
 	}
 	for _, tt := range tests {
@@ -169,7 +181,7 @@ func TestComplex64p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Complex64p(tt.args.key, tt.args.value),
+				unpack(Complex64p(tt.args.key, tt.args.value)),
 				"Complex64p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -181,7 +193,7 @@ func TestComplex64p(t *testing.T) {
 func TestDuration(t *testing.T) {
 	key := "key"
 	f := Duration(key, time.Second)
-	assert.Equal(t, field{time.Second, key, DurationType}, f)
+	assert.Equal(t, tField{time.Second, key, DurationType}, unpack(f))
 }
 
 func TestDurationp(t *testing.T) {
@@ -192,18 +204,18 @@ func TestDurationp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*time.Duration)(nil), "key", UnknownType},
+			want: tField{(*time.Duration)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", DurationType},
+			want: tField{v, "key", DurationType},
 		},
 	}
 	for _, tt := range tests {
@@ -211,7 +223,7 @@ func TestDurationp(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Durationp(tt.args.key, tt.args.value),
+				unpack(Durationp(tt.args.key, tt.args.value)),
 				"Durationp(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -223,7 +235,7 @@ func TestDurationp(t *testing.T) {
 func TestFloat64(t *testing.T) {
 	key := "key"
 	f := Float64(key, 1.2)
-	assert.Equal(t, field{1.2, key, Float64Type}, f)
+	assert.Equal(t, tField{1.2, key, Float64Type}, unpack(f))
 }
 
 func TestFloat64p(t *testing.T) {
@@ -234,18 +246,18 @@ func TestFloat64p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*float64)(nil), "key", UnknownType},
+			want: tField{(*float64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Float64Type},
+			want: tField{v, "key", Float64Type},
 		},
 	}
 	for _, tt := range tests {
@@ -253,7 +265,7 @@ func TestFloat64p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Float64p(tt.args.key, tt.args.value),
+				unpack(Float64p(tt.args.key, tt.args.value)),
 				"Float64p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -265,7 +277,7 @@ func TestFloat64p(t *testing.T) {
 func TestFloat32(t *testing.T) {
 	key := "key"
 	f := Float32(key, float32(1.2))
-	assert.Equal(t, field{float32(1.2), key, Float32Type}, f)
+	assert.Equal(t, tField{float32(1.2), key, Float32Type}, unpack(f))
 }
 
 func TestFloat32p(t *testing.T) {
@@ -276,18 +288,18 @@ func TestFloat32p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*float32)(nil), "key", UnknownType},
+			want: tField{(*float32)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Float32Type},
+			want: tField{v, "key", Float32Type},
 		},
 	}
 	for _, tt := range tests {
@@ -295,7 +307,7 @@ func TestFloat32p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Float32p(tt.args.key, tt.args.value),
+				unpack(Float32p(tt.args.key, tt.args.value)),
 				"Float32p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -307,7 +319,7 @@ func TestFloat32p(t *testing.T) {
 func TestInt(t *testing.T) {
 	key := "key"
 	f := Int(key, 2)
-	assert.Equal(t, field{int64(2), key, Int64Type}, f)
+	assert.Equal(t, tField{int64(2), key, Int64Type}, unpack(f))
 }
 
 func TestIntp(t *testing.T) {
@@ -318,23 +330,23 @@ func TestIntp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*int64)(nil), "key", UnknownType},
+			want: tField{(*int64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{int64(v), "key", Int64Type},
+			want: tField{int64(v), "key", Int64Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Intp(tt.args.key, tt.args.value), "Intp(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Intp(tt.args.key, tt.args.value)), "Intp(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -342,7 +354,7 @@ func TestIntp(t *testing.T) {
 func TestInt64(t *testing.T) {
 	key := "key"
 	f := Int64(key, int64(2))
-	assert.Equal(t, field{int64(2), key, Int64Type}, f)
+	assert.Equal(t, tField{int64(2), key, Int64Type}, unpack(f))
 }
 
 func TestInt64p(t *testing.T) {
@@ -353,23 +365,23 @@ func TestInt64p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*int64)(nil), "key", UnknownType},
+			want: tField{(*int64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Int64Type},
+			want: tField{v, "key", Int64Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Int64p(tt.args.key, tt.args.value), "Int64p(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Int64p(tt.args.key, tt.args.value)), "Int64p(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -377,7 +389,7 @@ func TestInt64p(t *testing.T) {
 func TestInt32(t *testing.T) {
 	key := "key"
 	f := Int32(key, int32(2))
-	assert.Equal(t, field{int32(2), key, Int32Type}, f)
+	assert.Equal(t, tField{int32(2), key, Int32Type}, unpack(f))
 }
 
 func TestInt32p(t *testing.T) {
@@ -388,23 +400,23 @@ func TestInt32p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*int32)(nil), "key", UnknownType},
+			want: tField{(*int32)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Int32Type},
+			want: tField{v, "key", Int32Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Int32p(tt.args.key, tt.args.value), "Int32p(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Int32p(tt.args.key, tt.args.value)), "Int32p(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -412,7 +424,7 @@ func TestInt32p(t *testing.T) {
 func TestInt16(t *testing.T) {
 	key := "key"
 	f := Int16(key, int16(2))
-	assert.Equal(t, field{int16(2), key, Int16Type}, f)
+	assert.Equal(t, tField{int16(2), key, Int16Type}, unpack(f))
 }
 
 func TestInt16p(t *testing.T) {
@@ -423,23 +435,23 @@ func TestInt16p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*int16)(nil), "key", UnknownType},
+			want: tField{(*int16)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Int16Type},
+			want: tField{v, "key", Int16Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Int16p(tt.args.key, tt.args.value), "Int16p(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Int16p(tt.args.key, tt.args.value)), "Int16p(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -447,7 +459,7 @@ func TestInt16p(t *testing.T) {
 func TestInt8(t *testing.T) {
 	key := "key"
 	f := Int8(key, int8(2))
-	assert.Equal(t, field{int8(2), key, Int8Type}, f)
+	assert.Equal(t, tField{int8(2), key, Int8Type}, unpack(f))
 }
 
 func TestInt8p(t *testing.T) {
@@ -458,23 +470,23 @@ func TestInt8p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*int8)(nil), "key", UnknownType},
+			want: tField{(*int8)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Int8Type},
+			want: tField{v, "key", Int8Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Int8p(tt.args.key, tt.args.value), "Int8p(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Int8p(tt.args.key, tt.args.value)), "Int8p(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -482,7 +494,7 @@ func TestInt8p(t *testing.T) {
 func TestString(t *testing.T) {
 	key := "key"
 	f := String(key, "val")
-	assert.Equal(t, field{"val", key, StringType}, f)
+	assert.Equal(t, tField{"val", key, StringType}, unpack(f))
 }
 
 func TestStringp(t *testing.T) {
@@ -493,18 +505,18 @@ func TestStringp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*string)(nil), "key", UnknownType},
+			want: tField{(*string)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", StringType},
+			want: tField{v, "key", StringType},
 		},
 	}
 	for _, tt := range tests {
@@ -512,7 +524,7 @@ func TestStringp(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Stringp(tt.args.key, tt.args.value),
+				unpack(Stringp(tt.args.key, tt.args.value)),
 				"Stringp(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -525,7 +537,13 @@ func TestTime(t *testing.T) {
 	key := "key"
 	val := time.Now()
 	f := Time(key, val)
-	assert.Equal(t, field{val, key, TimeType}, f)
+	// monotonic clock reading is not kept
+	assert.Equal(t, tField{val.Round(0), key, TimeType}, unpack(f))
+	assert.Equal(t, val.Round(0), f.TimeValue())
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	f = Time(key, far)
+	assert.Equal(t, tField{far, key, TimeType}, unpack(f))
+	assert.Equal(t, time.Unix(0, 1), Attr{typ: TimeType, num: 1}.TimeValue())
 }
 
 func TestTimep(t *testing.T) {
@@ -536,23 +554,23 @@ func TestTimep(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*time.Time)(nil), "key", UnknownType},
+			want: tField{(*time.Time)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", TimeType},
+			want: tField{v.Round(0), "key", TimeType},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Timep(tt.args.key, tt.args.value), "Timep(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Timep(tt.args.key, tt.args.value)), "Timep(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -560,10 +578,11 @@ func TestTimep(t *testing.T) {
 func TestNewContext(t *testing.T) {
 	field := sf("foo", "bar")
 	ctx := NewContext(context.Background(), field)
-	fields, ok := ctx.Value(fieldKey{}).([]Field)
+	set, ok := ctx.Value(fieldKey{}).(FieldSet)
 	if !ok {
 		t.Fatalf("expect ok, but not")
 	}
+	fields := set.Fields()
 	if len(fields) != 1 {
 		t.Fatalf("expect len 1, got %v", len(fields))
 	}
@@ -571,8 +590,9 @@ func TestNewContext(t *testing.T) {
 		t.Errorf("want %v, got %v", field, fields[0])
 	}
 	ctx = NewContext(ctx, sf("a", "b"))
-	fields, ok = ctx.Value(fieldKey{}).([]Field)
+	set, ok = ctx.Value(fieldKey{}).(FieldSet)
 	assert.True(t, ok)
+	fields = set.Fields()
 	assert.Len(t, fields, 2)
 	assert.Equal(t, field, fields[0])
 	assert.Equal(t, sf("a", "b"), fields[1])
@@ -581,7 +601,7 @@ func TestNewContext(t *testing.T) {
 func TestUint(t *testing.T) {
 	key := "key"
 	f := Uint(key, uint(2))
-	assert.Equal(t, field{uint64(2), key, Uint64Type}, f)
+	assert.Equal(t, tField{uint64(2), key, Uint64Type}, unpack(f))
 }
 
 func TestUintp(t *testing.T) {
@@ -592,23 +612,23 @@ func TestUintp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uint64)(nil), "key", UnknownType},
+			want: tField{(*uint64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{uint64(v), "key", Uint64Type},
+			want: tField{uint64(v), "key", Uint64Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Uintp(tt.args.key, tt.args.value), "Uintp(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Uintp(tt.args.key, tt.args.value)), "Uintp(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -616,7 +636,7 @@ func TestUintp(t *testing.T) {
 func TestUint64(t *testing.T) {
 	key := "key"
 	f := Uint64(key, uint64(2))
-	assert.Equal(t, field{uint64(2), key, Uint64Type}, f)
+	assert.Equal(t, tField{uint64(2), key, Uint64Type}, unpack(f))
 }
 
 func TestUint64p(t *testing.T) {
@@ -627,18 +647,18 @@ func TestUint64p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uint64)(nil), "key", UnknownType},
+			want: tField{(*uint64)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Uint64Type},
+			want: tField{v, "key", Uint64Type},
 		},
 	}
 	for _, tt := range tests {
@@ -646,7 +666,7 @@ func TestUint64p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Uint64p(tt.args.key, tt.args.value),
+				unpack(Uint64p(tt.args.key, tt.args.value)),
 				"Uint64p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -658,7 +678,7 @@ func TestUint64p(t *testing.T) {
 func TestUint32(t *testing.T) {
 	key := "key"
 	f := Uint32(key, uint32(2))
-	assert.Equal(t, field{uint32(2), key, Uint32Type}, f)
+	assert.Equal(t, tField{uint32(2), key, Uint32Type}, unpack(f))
 }
 
 func TestUint32p(t *testing.T) {
@@ -669,18 +689,18 @@ func TestUint32p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uint32)(nil), "key", UnknownType},
+			want: tField{(*uint32)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Uint32Type},
+			want: tField{v, "key", Uint32Type},
 		},
 	}
 	for _, tt := range tests {
@@ -688,7 +708,7 @@ func TestUint32p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Uint32p(tt.args.key, tt.args.value),
+				unpack(Uint32p(tt.args.key, tt.args.value)),
 				"Uint32p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -700,7 +720,7 @@ func TestUint32p(t *testing.T) {
 func TestUint16(t *testing.T) {
 	key := "key"
 	f := Uint16(key, uint16(2))
-	assert.Equal(t, field{uint16(2), key, Uint16Type}, f)
+	assert.Equal(t, tField{uint16(2), key, Uint16Type}, unpack(f))
 }
 
 func TestUint16p(t *testing.T) {
@@ -711,18 +731,18 @@ func TestUint16p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uint16)(nil), "key", UnknownType},
+			want: tField{(*uint16)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Uint16Type},
+			want: tField{v, "key", Uint16Type},
 		},
 	}
 	for _, tt := range tests {
@@ -730,7 +750,7 @@ func TestUint16p(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Uint16p(tt.args.key, tt.args.value),
+				unpack(Uint16p(tt.args.key, tt.args.value)),
 				"Uint16p(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -742,7 +762,7 @@ func TestUint16p(t *testing.T) {
 func TestUint8(t *testing.T) {
 	key := "key"
 	f := Uint8(key, uint8(2))
-	assert.Equal(t, field{uint8(2), key, Uint8Type}, f)
+	assert.Equal(t, tField{uint8(2), key, Uint8Type}, unpack(f))
 }
 
 func TestUint8p(t *testing.T) {
@@ -753,23 +773,23 @@ func TestUint8p(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uint8)(nil), "key", UnknownType},
+			want: tField{(*uint8)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", Uint8Type},
+			want: tField{v, "key", Uint8Type},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, Uint8p(tt.args.key, tt.args.value), "Uint8p(%v, %v)", tt.args.key, tt.args.value)
+			assert.Equalf(t, tt.want, unpack(Uint8p(tt.args.key, tt.args.value)), "Uint8p(%v, %v)", tt.args.key, tt.args.value)
 		})
 	}
 }
@@ -778,7 +798,7 @@ func TestUintptr(t *testing.T) {
 	key := "key"
 	val := (uintptr)(unsafe.Pointer(&key))
 	f := Uintptr(key, val)
-	assert.Equal(t, field{val, key, UintptrType}, f)
+	assert.Equal(t, tField{val, key, UintptrType}, unpack(f))
 }
 
 func TestUintptrp(t *testing.T) {
@@ -790,18 +810,18 @@ func TestUintptrp(t *testing.T) {
 	}
 	tests := []struct {
 		args args
-		want Field
+		want tField
 		name string
 	}{
 		{
 			name: "nil",
 			args: args{nil, "key"},
-			want: field{(*uintptr)(nil), "key", UnknownType},
+			want: tField{(*uintptr)(nil), "key", UnknownType},
 		},
 		{
 			name: "v",
 			args: args{&v, "key"},
-			want: field{v, "key", UintptrType},
+			want: tField{v, "key", UintptrType},
 		},
 	}
 	for _, tt := range tests {
@@ -809,7 +829,7 @@ func TestUintptrp(t *testing.T) {
 			assert.Equalf(
 				t,
 				tt.want,
-				Uintptrp(tt.args.key, tt.args.value),
+				unpack(Uintptrp(tt.args.key, tt.args.value)),
 				"Uintptrp(%v, %v)",
 				tt.args.key,
 				tt.args.value,
@@ -822,32 +842,42 @@ func TestStringer(t *testing.T) {
 	key := "key"
 	val := hanBoolStringer(true)
 	f := Stringer(key, val)
-	assert.Equal(t, field{val, key, StringerType}, f)
+	assert.Equal(t, tField{val, key, StringerType}, unpack(f))
 }
 
 func TestError(t *testing.T) {
 	val := io.EOF
 	f := Error(val)
-	assert.Equal(t, field{val, "error", ErrorType}, f)
+	assert.Equal(t, tField{val, "error", ErrorType}, unpack(f))
 }
 
 func TestNamedError(t *testing.T) {
 	key := "key"
 	val := io.EOF
 	f := NamedError(key, val)
-	assert.Equal(t, field{val, key, ErrorType}, f)
+	assert.Equal(t, tField{val, key, ErrorType}, unpack(f))
 }
 
 func TestStack(t *testing.T) {
 	key := "key"
 	f := Stack(key)
-	assert.Equal(t, field{0, "key", StackType}, f)
+	assert.Equal(t, key, f.Key())
+	assert.Equal(t, StackType, f.Type())
+	frames := f.Value().(*Stacktrace).Frames()
+	assert.Equal(t, "github.com/yimi-go/logging.TestStack", frames[0].Function)
+}
+
+func stackSkipHelper(key string) Field {
+	return StackSkip(key, 1)
 }
 
 func TestStackSkip(t *testing.T) {
 	key := "key"
-	f := StackSkip(key, 3)
-	assert.Equal(t, field{3, "key", StackType}, f)
+	f := stackSkipHelper(key)
+	assert.Equal(t, key, f.Key())
+	assert.Equal(t, StackType, f.Type())
+	frames := f.Value().(*Stacktrace).Frames()
+	assert.Equal(t, "github.com/yimi-go/logging.TestStackSkip", frames[0].Function)
 }
 
 func TestWithContextField(t *testing.T) {
@@ -875,3 +905,184 @@ func TestWithContextField(t *testing.T) {
 		}
 	})
 }
+
+func TestGroup(t *testing.T) {
+	fields := []Field{sf("method", "GET"), Int("status", 200)}
+	f := Group("http", fields...)
+	assert.Equal(t, tField{fields, "http", GroupType}, unpack(f))
+	fields[0] = sf("method", "POST")
+	assert.Equal(t, sf("method", "GET"), f.Value().([]Field)[0])
+}
+
+func TestNamespace(t *testing.T) {
+	f := Namespace("http")
+	assert.Equal(t, tField{nil, "http", NamespaceType}, unpack(f))
+}
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	f := Lazy("key", func() any {
+		calls++
+		return "value"
+	})
+	assert.Equal(t, "key", f.Key())
+	assert.Equal(t, LazyType, f.Type())
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, sf("key", "value"), Resolve(f))
+	assert.Equal(t, sf("key", "value"), Resolve(f))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, Int("wanted", 1), Resolve(Lazy("wanted", func() any { return Int("other", 1) })))
+}
+
+func TestInfer(t *testing.T) {
+	assert.Equal(t, sf("key", "value"), Infer("key", "value"))
+	assert.Equal(t, Int("key", 1), Infer("key", 1))
+	assert.Equal(t, NamedError("key", io.EOF), Infer("key", io.EOF))
+	assert.Equal(t, Any("key", nil), Infer("key", nil))
+	assert.Equal(t, Any("key", []int{1}), Infer("key", []int{1}))
+	assert.Equal(t, Int("key", 1), Infer("key", Int("other", 1)))
+}
+
+func TestLazyField(t *testing.T) {
+	calls := 0
+	f := LazyField(func() Field {
+		calls++
+		return Int("key", 1)
+	})
+	assert.Equal(t, "", f.Key())
+	assert.Equal(t, LazyType, f.Type())
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, Int("key", 1), Resolve(f))
+	assert.Equal(t, Int("key", 1), Resolve(f))
+	assert.Equal(t, 1, calls)
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		in   Field
+		want Field
+		name string
+	}{
+		{
+			name: "not_lazy",
+			in:   sf("key", "value"),
+			want: sf("key", "value"),
+		},
+		{
+			name: "nested",
+			in: LazyField(func() Field {
+				return Lazy("key", func() any { return true })
+			}),
+			want: Bool("key", true),
+		},
+		{
+			name: "zero_field",
+			in:   LazyField(func() Field { return Attr{} }),
+			want: Any("", nil),
+		},
+		{
+			name: "bad_value",
+			in:   Attr{iface: 123, key: "key", typ: LazyType},
+			want: Any("key", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resolve(tt.in))
+		})
+	}
+}
+
+func Test_inferField(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		value any
+		want  FieldType
+	}{
+		{nil, UnknownType},
+		{[]byte("a"), BinaryType},
+		{true, BoolType},
+		{1 + 2i, Complex128Type},
+		{complex64(1 + 2i), Complex64Type},
+		{time.Second, DurationType},
+		{1.0, Float64Type},
+		{float32(1), Float32Type},
+		{1, Int64Type},
+		{int64(1), Int64Type},
+		{int32(1), Int32Type},
+		{int16(1), Int16Type},
+		{int8(1), Int8Type},
+		{"a", StringType},
+		{now, TimeType},
+		{uint(1), Uint64Type},
+		{uint64(1), Uint64Type},
+		{uint32(1), Uint32Type},
+		{uint16(1), Uint16Type},
+		{uint8(1), Uint8Type},
+		{uintptr(1), UintptrType},
+		{io.EOF, ErrorType},
+		{hanBoolStringer(true), StringerType},
+		{map[string]any{}, UnknownType},
+	}
+	for _, tt := range tests {
+		f := inferField("key", tt.value)
+		assert.Equal(t, "key", f.Key())
+		assert.Equalf(t, tt.want, f.Type(), "inferField(%T)", tt.value)
+	}
+	assert.Equal(t, sf("key", "v"), inferField("key", sf("other", "v")))
+}
+
+func TestRename(t *testing.T) {
+	assert.Equal(t, sf("new", "v"), Rename(sf("old", "v"), "new"))
+	f := Rename(LazyField(func() Field { return sf("old", "v") }), "new")
+	assert.Equal(t, "new", f.Key())
+	assert.Equal(t, LazyType, f.Type())
+	assert.Equal(t, sf("new", "v"), Resolve(f))
+}
+
+func TestField_accessors(t *testing.T) {
+	now := time.Now()
+	assert.True(t, Bool("k", true).BoolValue())
+	assert.False(t, Bool("k", false).BoolValue())
+	assert.Equal(t, time.Second, Duration("k", time.Second).DurationValue())
+	assert.Equal(t, 1.5, Float64("k", 1.5).Float64Value())
+	assert.Equal(t, 1.5, Float32("k", 1.5).Float64Value())
+	assert.Equal(t, int64(-1), Int8("k", -1).Int64Value())
+	assert.Equal(t, uint64(math.MaxUint64), Uint64("k", math.MaxUint64).Uint64Value())
+	assert.Equal(t, uint64(math.MaxUint64), Uint64("k", math.MaxUint64).Value())
+	assert.Equal(t, "v", String("k", "v").StringValue())
+	assert.Equal(t, now.Round(0), Time("k", now).TimeValue())
+	assert.Equal(t, io.EOF, Error(io.EOF).Interface())
+}
+
+// extField is a Field implemented outside of Attr.
+type extField struct {
+	key string
+	typ FieldType
+	val any
+}
+
+func (f extField) Key() string     { return f.key }
+func (f extField) Type() FieldType { return f.typ }
+func (f extField) Value() any      { return f.val }
+
+func TestAttrOf(t *testing.T) {
+	a := Int("k", 1)
+	assert.Equal(t, a, AttrOf(a))
+	assert.Equal(t, a, AttrOf(&a))
+	assert.Equal(t, Attr{}, AttrOf((*Attr)(nil)))
+	assert.Equal(t, Attr{}, AttrOf(nil))
+
+	now := time.Now()
+	assert.Equal(t, int64(2), AttrOf(extField{"k", Int64Type, int64(2)}).Int64Value())
+	assert.Equal(t, "v", AttrOf(extField{"k", StringType, "v"}).StringValue())
+	assert.Equal(t, now.Round(0), AttrOf(extField{"k", TimeType, now}).TimeValue())
+	assert.Equal(t, Uint8("k", 3), AttrOf(extField{"k", Uint8Type, uint8(3)}))
+	assert.Equal(t, Any("k", "v"), AttrOf(extField{"k", UnknownType, "v"}))
+	assert.Equal(t, NamedError("k", io.EOF), AttrOf(extField{"k", ErrorType, io.EOF}))
+
+	set := NewFieldSet(KeepAll, extField{"k", StringType, "v"})
+	f, ok := set.Get("k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", f.StringValue())
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"strconv"
+
+	"go.uber.org/atomic"
+)
+
+// MergePolicy is the way Fields with duplicate keys are merged.
+type MergePolicy uint32
+
+const (
+	// KeepAll keeps all the Fields, leaving duplicate keys to vendors.
+	KeepAll MergePolicy = iota
+	// LastWins keeps the later one of the Fields with the same key,
+	// at the position of the former one.
+	LastWins
+	// FirstWins keeps the former one of the Fields with the same key.
+	FirstWins
+	// SuffixRename renames the later ones of the Fields with the same key by suffixing
+	// their keys with "_1", "_2" and so on.
+	SuffixRename
+)
+
+var mergePolicyStore = atomic.NewUint32(uint32(LastWins))
+
+// GetMergePolicy returns the MergePolicy of the process, LastWins by default,
+// i.e. Fields added to contexts replace the ones with the same keys.
+func GetMergePolicy() MergePolicy {
+	return MergePolicy(mergePolicyStore.Load())
+}
+
+// SwapMergePolicy sets the MergePolicy of the process, and returns the origin MergePolicy.
+// The MergePolicy is honored by NewContext when merging Fields into the context.
+// It is expected to be called at initialization, the same as SwapFactory.
+func SwapMergePolicy(policy MergePolicy) MergePolicy {
+	return MergePolicy(mergePolicyStore.Swap(uint32(policy)))
+}
+
+// FieldSet is an immutable ordered set of Fields, whose duplicate keys are merged by a MergePolicy.
+//
+// Keys are scoped by the preceding Namespace Fields, so that Fields nested under
+// different Namespaces never conflict. Fields created by LazyField, whose keys are unknown
+// until resolved, and Namespace Fields are always kept.
+//
+// The zero FieldSet is an empty FieldSet of KeepAll.
+type FieldSet struct {
+	fields []Field
+	policy MergePolicy
+}
+
+// NewFieldSet creates a FieldSet of policy with the given Fields.
+func NewFieldSet(policy MergePolicy, field ...Field) FieldSet {
+	return FieldSet{policy: policy}.With(field...)
+}
+
+// Policy returns the MergePolicy of the FieldSet.
+func (s FieldSet) Policy() MergePolicy { return s.policy }
+
+// Len returns the number of Fields in the FieldSet.
+func (s FieldSet) Len() int { return len(s.fields) }
+
+// Fields returns the Fields of the FieldSet, which must not be modified.
+func (s FieldSet) Fields() []Field { return s.fields }
+
+// Get returns the first Field with key, in any scope, as an Attr, and reports whether it exists.
+func (s FieldSet) Get(key string) (Attr, bool) {
+	for _, f := range s.fields {
+		if f.Key() == key && f.Type() != NamespaceType {
+			return AttrOf(f), true
+		}
+	}
+	return Attr{}, false
+}
+
+// Without returns a new FieldSet without the Fields with any of keys, in any scope.
+// Namespace Fields are kept. s is not modified.
+func (s FieldSet) Without(keys ...string) FieldSet {
+	if len(keys) == 0 {
+		return s
+	}
+	fields := make([]Field, 0, len(s.fields))
+	for _, f := range s.fields {
+		if f.Type() == NamespaceType || !containsKey(keys, f.Key()) {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == len(s.fields) {
+		return s
+	}
+	return FieldSet{fields: fields, policy: s.policy}
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// With returns a new FieldSet with the given Fields merged after the Fields of s.
+// s is not modified, and the new FieldSet never shares memory with s.
+func (s FieldSet) With(field ...Field) FieldSet {
+	if len(field) == 0 {
+		return s
+	}
+	fields := make([]Field, 0, len(s.fields)+len(field))
+	fields = append(fields, s.fields...)
+	fields = append(fields, field...)
+	return FieldSet{fields: mergeFields(s.policy, fields), policy: s.policy}
+}
+
+// mergeFields merges the duplicate keys of fields in place by policy, and returns the merged ones.
+func mergeFields(policy MergePolicy, fields []Field) []Field {
+	if policy == KeepAll || len(fields) < 2 {
+		return fields
+	}
+	type scopedKey struct {
+		scope int
+		key   string
+	}
+	index := make(map[scopedKey]int, len(fields))
+	merged := fields[:0]
+	scope := 0
+	for _, f := range fields {
+		if f.Type() == NamespaceType {
+			scope++
+			merged = append(merged, f)
+			continue
+		}
+		if f.Type() == LazyType && f.Key() == "" {
+			merged = append(merged, f)
+			continue
+		}
+		sk := scopedKey{scope: scope, key: f.Key()}
+		i, dup := index[sk]
+		if !dup {
+			index[sk] = len(merged)
+			merged = append(merged, f)
+			continue
+		}
+		switch policy {
+		case LastWins:
+			merged[i] = f
+		case SuffixRename:
+			for n := 1; ; n++ {
+				renamed := scopedKey{scope: scope, key: f.Key() + "_" + strconv.Itoa(n)}
+				if _, ok := index[renamed]; !ok {
+					index[renamed] = len(merged)
+					merged = append(merged, Rename(f, renamed.key))
+					break
+				}
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwapMergePolicy(t *testing.T) {
+	assert.Equal(t, LastWins, GetMergePolicy())
+	assert.Equal(t, LastWins, SwapMergePolicy(KeepAll))
+	assert.Equal(t, KeepAll, GetMergePolicy())
+	assert.Equal(t, KeepAll, SwapMergePolicy(LastWins))
+}
+
+func TestFieldSet(t *testing.T) {
+	fields := []Field{
+		Int("a", 1),
+		Int("b", 1),
+		Int("a", 2),
+		Namespace("ns"),
+		Int("a", 3),
+		LazyField(func() Field { return Int("a", 4) }),
+		Int("a", 5),
+		Int("a_1", 6),
+		Int("a", 7),
+	}
+	tests := []struct {
+		policy MergePolicy
+		want   []Field
+	}{
+		{KeepAll, fields},
+		{LastWins, []Field{Int("a", 2), Int("b", 1), Namespace("ns"), Int("a", 7), fields[5], Int("a_1", 6)}},
+		{FirstWins, []Field{Int("a", 1), Int("b", 1), Namespace("ns"), Int("a", 3), fields[5], Int("a_1", 6)}},
+		{SuffixRename, []Field{
+			Int("a", 1), Int("b", 1), Int("a_1", 2), Namespace("ns"),
+			Int("a", 3), fields[5], Int("a_1", 5), Int("a_1_1", 6), Int("a_2", 7),
+		}},
+	}
+	for _, tt := range tests {
+		set := NewFieldSet(tt.policy, fields...)
+		assert.Equal(t, tt.policy, set.Policy())
+		assert.Equal(t, len(tt.want), set.Len())
+		for i, f := range tt.want {
+			if f.Type() == LazyType {
+				assert.Equal(t, LazyType, set.Fields()[i].Type())
+				continue
+			}
+			assert.Equal(t, f, set.Fields()[i], "policy %d index %d", tt.policy, i)
+		}
+	}
+}
+
+func TestFieldSet_With(t *testing.T) {
+	var empty FieldSet
+	assert.Equal(t, KeepAll, empty.Policy())
+	assert.Equal(t, empty, empty.With())
+
+	base := NewFieldSet(LastWins, Int("a", 1), Int("b", 1))
+	s1 := base.With(Int("a", 2))
+	s2 := base.With(Int("c", 3))
+	assert.Equal(t, []Field{Int("a", 1), Int("b", 1)}, base.Fields())
+	assert.Equal(t, []Field{Int("a", 2), Int("b", 1)}, s1.Fields())
+	assert.Equal(t, []Field{Int("a", 1), Int("b", 1), Int("c", 3)}, s2.Fields())
+
+	renamed := NewFieldSet(SuffixRename, Lazy("a", func() any { return 1 })).With(Lazy("a", func() any { return 2 }))
+	assert.Equal(t, Int("a_1", 2), Resolve(renamed.Fields()[1]))
+}
+
+func TestFieldSet_Without(t *testing.T) {
+	set := NewFieldSet(KeepAll, Int("a", 1), Int("b", 1), Namespace("a"), Int("a", 2))
+	assert.Equal(t, []Field{Int("b", 1), Namespace("a")}, set.Without("a", "c").Fields())
+	assert.Equal(t, set, set.Without("c"))
+	assert.Equal(t, set, set.Without())
+	assert.Len(t, set.Fields(), 4)
+
+	f, ok := set.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, Int("a", 1), f)
+	_, ok = set.Get("c")
+	assert.False(t, ok)
+}
+
+func TestNewContext_mergePolicy(t *testing.T) {
+	ctx := NewContext(context.Background(), Int("a", 1))
+	ctx = NewContext(ctx, Int("a", 2), Int("b", 1))
+	nl := WithContextField(ctx, &tl{}).(*tl)
+	assert.Equal(t, []Field{Int("a", 2), Int("b", 1)}, nl.field)
+
+	defer SwapMergePolicy(SwapMergePolicy(KeepAll))
+	ctx = NewContext(context.Background(), Int("a", 1))
+	ctx = NewContext(ctx, Int("a", 2))
+	assert.Equal(t, []Field{Int("a", 1), Int("a", 2)}, FieldsFromContext(ctx).Fields())
+}
+
+func TestNewContextWithout(t *testing.T) {
+	bg := context.Background()
+	assert.Equal(t, bg, NewContextWithout(bg, "a"))
+	assert.Equal(t, 0, FieldsFromContext(bg).Len())
+	assert.Equal(t, LastWins, FieldsFromContext(bg).Policy())
+
+	ctx := NewContext(bg, Int("a", 1), Int("b", 1))
+	assert.Equal(t, ctx, NewContextWithout(ctx, "c"))
+	without := NewContextWithout(ctx, "a")
+	assert.Equal(t, []Field{Int("b", 1)}, FieldsFromContext(without).Fields())
+	assert.Equal(t, []Field{Int("a", 1), Int("b", 1)}, FieldsFromContext(ctx).Fields())
+	assert.Equal(t, []Field{Int("b", 1), Int("a", 2)}, FieldsFromContext(NewContext(without, Int("a", 2))).Fields())
+}
+
+func TestNewContext_noAliasing(t *testing.T) {
+	parent := NewContext(context.Background(), Int("a", 0))
+	// leave spare capacity in the parent, which appending would share.
+	parent = NewContextWithout(NewContext(parent, Int("x", 0), Int("y", 0)), "x", "y")
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := NewContext(parent, Int("n", i))
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, []Field{Int("a", 0), Int("n", i)}, FieldsFromContext(ctx).Fields())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -1,14 +1,21 @@
 module github.com/yimi-go/logging
 
-go 1.18
+go 1.21
 
 require (
+	github.com/go-logr/logr v1.4.2
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/atomic v1.9.0
+	google.golang.org/grpc v1.64.1
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
 )
@@ -0,0 +1,27 @@
+package logging
+
+// GroupLogger is an optional interface that a vendor Logger could implement
+// to support nesting fields natively.
+type GroupLogger interface {
+	// WithGroup returns a new Logger that nests all the subsequent Fields under name,
+	// including the ones added via WithField and the ones passed to logging methods.
+	// Fields of current Logger are kept as they were.
+	// This method should never return nil.
+	WithGroup(name string) Logger
+}
+
+// WithGroup returns a Logger that nests all the subsequent Fields under name.
+// It works like WithField, but for nesting.
+//
+// If logger implements GroupLogger, its WithGroup method is used.
+// Otherwise, a Namespace Field is added to the logger via WithField.
+// If name is empty, the origin logger is returned.
+func WithGroup(logger Logger, name string) Logger {
+	if name == "" {
+		return logger
+	}
+	if gl, ok := logger.(GroupLogger); ok {
+		return gl.WithGroup(name)
+	}
+	return logger.WithField(Namespace(name))
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tgl struct {
+	tl
+	group []string
+}
+
+func (t *tgl) WithGroup(name string) Logger {
+	return &tgl{tl: t.tl, group: append(t.group, name)}
+}
+
+func TestWithGroup(t *testing.T) {
+	t.Run("empty_name", func(t *testing.T) {
+		l := &tl{}
+		assert.Same(t, l, WithGroup(l, ""))
+	})
+	t.Run("group_logger", func(t *testing.T) {
+		l := &tgl{}
+		nl := WithGroup(l, "http").(*tgl)
+		assert.Equal(t, []string{"http"}, nl.group)
+		assert.Empty(t, nl.field)
+	})
+	t.Run("fallback", func(t *testing.T) {
+		l := &tl{}
+		nl := WithGroup(l, "http").(*tl)
+		assert.Equal(t, []Field{Namespace("http")}, nl.field)
+	})
+}
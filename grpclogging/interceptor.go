@@ -0,0 +1,270 @@
+package grpclogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/yimi-go/logging"
+)
+
+// The keys of the Fields logged by the interceptors, following the OpenTelemetry
+// semantic conventions where they exist.
+const (
+	// SystemKey is the key of the RPC system, always "grpc".
+	SystemKey = "rpc.system"
+	// ServiceKey is the key of the full name of the service, e.g. "grpc.health.v1.Health".
+	ServiceKey = "rpc.service"
+	// MethodKey is the key of the name of the method, e.g. "Check".
+	MethodKey = "rpc.method"
+	// RequestIDKey is the key of the request ID found in the metadata.
+	RequestIDKey = "request.id"
+	// PeerAddressKey is the key of the address of the peer.
+	PeerAddressKey = "network.peer.address"
+	// StatusCodeKey is the key of the numeric status code of the call.
+	StatusCodeKey = "rpc.grpc.status_code"
+	// DurationKey is the key of the duration of the call.
+	DurationKey = "rpc.duration"
+)
+
+// DefaultRequestIDKey is the metadata key of request IDs, unless set via WithRequestIDKeys.
+const DefaultRequestIDKey = "x-request-id"
+
+// The messages of the logs of the interceptors.
+const (
+	ServerMessage = "finished server call"
+	ClientMessage = "finished client call"
+)
+
+type options struct {
+	levelOf       func(code codes.Code) logging.Level
+	requestIDKeys []string
+}
+
+// Option configures the interceptors.
+type Option func(o *options)
+
+// WithLevels sets the function mapping status codes to the Levels the calls are logged at,
+// DefaultLevel by default. Calls are not logged at OffLevel.
+func WithLevels(levelOf func(code codes.Code) logging.Level) Option {
+	return func(o *options) {
+		o.levelOf = levelOf
+	}
+}
+
+// WithRequestIDKeys sets the metadata keys searched in order for request IDs,
+// DefaultRequestIDKey by default. The first one is used to propagate request IDs.
+func WithRequestIDKeys(keys ...string) Option {
+	return func(o *options) {
+		o.requestIDKeys = keys
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{levelOf: DefaultLevel, requestIDKeys: []string{DefaultRequestIDKey}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// DefaultLevel maps the status codes caused by the callers to InfoLevel,
+// the ones of the conditions worth attention to WarnLevel, and the server failures to ErrorLevel.
+func DefaultLevel(code codes.Code) logging.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.Unauthenticated:
+		return logging.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return logging.WarnLevel
+	default:
+		return logging.ErrorLevel
+	}
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor putting the Fields of the calls
+// into the contexts via logging.NewContext, and logging the calls when finished via logger,
+// with the Fields in the contexts added.
+func UnaryServerInterceptor(logger logging.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = o.serverContext(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		o.log(logging.WithContextField(ctx, logger), ServerMessage, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor but for streaming calls.
+func StreamServerInterceptor(logger logging.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := o.serverContext(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		o.log(logging.WithContextField(ctx, logger), ServerMessage, err, time.Since(start))
+		return err
+	}
+}
+
+// serverStream replaces the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// serverContext returns a context with the Fields of the incoming call.
+func (o *options) serverContext(ctx context.Context, fullMethod string) context.Context {
+	fields := methodFields(fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, logging.String(PeerAddressKey, p.Addr.String()))
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if id := o.requestID(md); id != "" {
+			fields = append(fields, logging.String(RequestIDKey, id))
+		}
+	}
+	return logging.NewContext(ctx, fields...)
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor logging the calls when finished
+// via logger, with the Fields in the contexts added.
+//
+// The request IDs in the outgoing metadata are logged. If there is none, the one in the
+// context Fields keyed by RequestIDKey, e.g. put by the server interceptors, is propagated.
+func UnaryClientInterceptor(logger logging.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = o.clientContext(ctx, method)
+		p := &peer.Peer{}
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(p))...)
+		o.log(clientLogger(ctx, logger, p), ClientMessage, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor is like UnaryClientInterceptor but for streaming calls,
+// which are logged when the streams are finished or fail to receive messages.
+func StreamClientInterceptor(logger logging.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = o.clientContext(ctx, method)
+		p := &peer.Peer{}
+		cs, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			o.log(clientLogger(ctx, logger, p), ClientMessage, err, time.Since(start))
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams, finish: func(err error) {
+			o.log(clientLogger(ctx, logger, p), ClientMessage, err, time.Since(start))
+		}}, nil
+	}
+}
+
+// clientStream calls finish once when the stream is finished.
+type clientStream struct {
+	grpc.ClientStream
+	finish        func(err error)
+	once          sync.Once
+	serverStreams bool
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil || !s.serverStreams {
+		if errors.Is(err, io.EOF) {
+			s.once.Do(func() { s.finish(nil) })
+		} else {
+			s.once.Do(func() { s.finish(err) })
+		}
+	}
+	return err
+}
+
+// clientContext returns a context with the Fields of the outgoing call,
+// and the request ID propagated if needed.
+func (o *options) clientContext(ctx context.Context, fullMethod string) context.Context {
+	fields := methodFields(fullMethod)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	id := o.requestID(md)
+	if id == "" && len(o.requestIDKeys) > 0 {
+		if f, ok := logging.FieldsFromContext(ctx).Get(RequestIDKey); ok && f.Type() == logging.StringType {
+			id = f.StringValue()
+			ctx = metadata.AppendToOutgoingContext(ctx, o.requestIDKeys[0], id)
+		}
+	}
+	if id != "" {
+		fields = append(fields, logging.String(RequestIDKey, id))
+	}
+	return logging.NewContext(ctx, fields...)
+}
+
+func clientLogger(ctx context.Context, logger logging.Logger, p *peer.Peer) logging.Logger {
+	logger = logging.WithContextField(ctx, logger)
+	if p.Addr != nil {
+		logger = logger.WithField(logging.String(PeerAddressKey, p.Addr.String()))
+	}
+	return logger
+}
+
+// requestID returns the first value of the request ID keys in md.
+func (o *options) requestID(md metadata.MD) string {
+	for _, key := range o.requestIDKeys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// log logs a finished call via logger at the Level of the status code of err.
+func (o *options) log(logger logging.Logger, message string, err error, duration time.Duration) {
+	code := status.Code(err)
+	lvl := o.levelOf(code)
+	if lvl == logging.OffLevel || !logger.Enabled(lvl) {
+		return
+	}
+	fields := make([]logging.Field, 0, 3)
+	fields = append(fields, logging.Int(StatusCodeKey, int(code)), logging.Duration(DurationKey, duration))
+	if err != nil {
+		fields = append(fields, logging.Error(err))
+	}
+	switch lvl {
+	case logging.DebugLevel:
+		logger.Debugw(message, fields...)
+	case logging.InfoLevel:
+		logger.Infow(message, fields...)
+	case logging.WarnLevel:
+		logger.Warnw(message, fields...)
+	default:
+		logger.Errorw(message, fields...)
+	}
+}
+
+// methodFields returns the Fields of a full method name, e.g. "/grpc.health.v1.Health/Check".
+func methodFields(fullMethod string) []logging.Field {
+	fields := make([]logging.Field, 0, 5)
+	fields = append(fields, logging.String(SystemKey, "grpc"))
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return append(fields, logging.String(MethodKey, fullMethod))
+	}
+	return append(fields, logging.String(ServiceKey, service), logging.String(MethodKey, method))
+}
@@ -0,0 +1,211 @@
+package grpclogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+// healthServer fails the services named "fail", and records the context Fields of the calls.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	fields []logging.FieldSet
+}
+
+func (s *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.fields = append(s.fields, logging.FieldsFromContext(ctx))
+	if req.Service == "fail" {
+		return nil, status.Error(codes.Internal, "failed")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(req *healthpb.HealthCheckRequest, ws healthpb.Health_WatchServer) error {
+	s.fields = append(s.fields, logging.FieldsFromContext(ws.Context()))
+	if req.Service == "fail" {
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+	return ws.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+type env struct {
+	server     *healthServer
+	client     healthpb.HealthClient
+	serverLogs *logtest.Recorder
+	clientLogs *logtest.Recorder
+}
+
+func newEnv(t *testing.T, opts ...Option) *env {
+	e := &env{
+		server:     &healthServer{},
+		serverLogs: logtest.New(logging.DebugLevel),
+		clientLogs: logtest.New(logging.DebugLevel),
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(e.serverLogs, opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(e.serverLogs, opts...)),
+	)
+	healthpb.RegisterHealthServer(srv, e.server)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(e.clientLogs, opts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(e.clientLogs, opts...)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	e.client = healthpb.NewHealthClient(conn)
+	return e
+}
+
+// value returns the value of the Field keyed by key in fields.
+func value(fields []logging.Field, key string) any {
+	for _, f := range fields {
+		if f.Key() == key {
+			return f.Value()
+		}
+	}
+	return nil
+}
+
+func TestUnary(t *testing.T) {
+	e := newEnv(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultRequestIDKey, "req-1")
+	_, err := e.client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = e.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "fail"})
+	require.Error(t, err)
+
+	require.Len(t, e.server.fields, 2)
+	set := e.server.fields[0]
+	id, ok := set.Get(RequestIDKey)
+	assert.True(t, ok)
+	assert.Equal(t, "req-1", id.StringValue())
+	method, _ := set.Get(MethodKey)
+	assert.Equal(t, "Check", method.StringValue())
+	_, ok = set.Get(PeerAddressKey)
+	assert.True(t, ok)
+	_, ok = e.server.fields[1].Get(RequestIDKey)
+	assert.False(t, ok)
+
+	for _, entries := range [][]logtest.Entry{e.serverLogs.Entries(), e.clientLogs.Entries()} {
+		require.Len(t, entries, 2)
+		assert.Equal(t, logging.InfoLevel, entries[0].Level)
+		assert.Equal(t, "grpc", value(entries[0].Fields, SystemKey))
+		assert.Equal(t, "grpc.health.v1.Health", value(entries[0].Fields, ServiceKey))
+		assert.Equal(t, "Check", value(entries[0].Fields, MethodKey))
+		assert.Equal(t, "req-1", value(entries[0].Fields, RequestIDKey))
+		assert.Equal(t, int64(codes.OK), value(entries[0].Fields, StatusCodeKey))
+		assert.NotNil(t, value(entries[0].Fields, DurationKey))
+		assert.NotNil(t, value(entries[0].Fields, PeerAddressKey))
+		assert.Nil(t, value(entries[0].Fields, "error"))
+
+		assert.Equal(t, logging.ErrorLevel, entries[1].Level)
+		assert.Equal(t, int64(codes.Internal), value(entries[1].Fields, StatusCodeKey))
+		assert.NotNil(t, value(entries[1].Fields, "error"))
+	}
+	assert.Equal(t, ServerMessage, e.serverLogs.Entries()[0].Message)
+	assert.Equal(t, ClientMessage, e.clientLogs.Entries()[0].Message)
+}
+
+func TestUnary_propagateRequestID(t *testing.T) {
+	e := newEnv(t, WithRequestIDKeys("x-trace", "x-request-id"))
+	ctx := logging.NewContext(context.Background(), logging.String(RequestIDKey, "req-2"))
+	_, err := e.client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	id, _ := e.server.fields[0].Get(RequestIDKey)
+	assert.Equal(t, "req-2", id.StringValue())
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-3")
+	_, err = e.client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	id, _ = e.server.fields[1].Get(RequestIDKey)
+	assert.Equal(t, "req-3", id.StringValue())
+	assert.Equal(t, "req-3", value(e.clientLogs.Entries()[1].Fields, RequestIDKey))
+}
+
+func TestStream(t *testing.T) {
+	e := newEnv(t, WithLevels(func(code codes.Code) logging.Level {
+		if code == codes.OK {
+			return logging.DebugLevel
+		}
+		return logging.WarnLevel
+	}))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultRequestIDKey, "req-4")
+	stream, err := e.client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.True(t, errors.Is(err, io.EOF))
+	_, _ = stream.Recv()
+
+	stream, err = e.client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "fail"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	id, _ := e.server.fields[0].Get(RequestIDKey)
+	assert.Equal(t, "req-4", id.StringValue())
+	for _, entries := range [][]logtest.Entry{e.serverLogs.Entries(), e.clientLogs.Entries()} {
+		require.Len(t, entries, 2)
+		assert.Equal(t, logging.DebugLevel, entries[0].Level)
+		assert.Equal(t, "Watch", value(entries[0].Fields, MethodKey))
+		assert.Equal(t, "req-4", value(entries[0].Fields, RequestIDKey))
+		assert.Equal(t, logging.WarnLevel, entries[1].Level)
+		assert.Equal(t, int64(codes.Unavailable), value(entries[1].Fields, StatusCodeKey))
+	}
+}
+
+func TestStreamClient_error(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	interceptor := StreamClientInterceptor(rec)
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "bad",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.Unimplemented, "no")
+		})
+	assert.Error(t, err)
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logging.ErrorLevel, entries[0].Level)
+	assert.Equal(t, "bad", value(entries[0].Fields, MethodKey))
+	assert.Nil(t, value(entries[0].Fields, ServiceKey))
+}
+
+func TestDefaultLevel(t *testing.T) {
+	assert.Equal(t, logging.InfoLevel, DefaultLevel(codes.OK))
+	assert.Equal(t, logging.InfoLevel, DefaultLevel(codes.NotFound))
+	assert.Equal(t, logging.WarnLevel, DefaultLevel(codes.DeadlineExceeded))
+	assert.Equal(t, logging.ErrorLevel, DefaultLevel(codes.Internal))
+	assert.Equal(t, logging.ErrorLevel, DefaultLevel(codes.Unknown))
+}
+
+func TestWithLevels_off(t *testing.T) {
+	e := newEnv(t, WithLevels(func(codes.Code) logging.Level { return logging.OffLevel }))
+	_, err := e.client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, e.serverLogs.Entries())
+	assert.Empty(t, e.clientLogs.Entries())
+}
@@ -0,0 +1,80 @@
+// Package grpclogging integrates gRPC with the logging facade: NewLoggerV2 adapts a Logger
+// to grpclog.LoggerV2 for the logs of gRPC itself, and the interceptors log the calls
+// with request-scoped Fields put into the contexts.
+package grpclogging
+
+import (
+	"os"
+
+	"google.golang.org/grpc/grpclog"
+
+	"github.com/yimi-go/logging"
+)
+
+// exit terminates the process after fatal logs, replaced in tests.
+var exit = os.Exit
+
+type loggerV2 struct {
+	logger    logging.Logger
+	verbosity int
+}
+
+// NewLoggerV2 creates a grpclog.LoggerV2 logging via logger, e.g. for grpclog.SetLoggerV2.
+// Verbose logs of gRPC are enabled up to verbosity.
+//
+// Warnings are logged at WarnLevel, and fatal logs at ErrorLevel before exiting with 1.
+// The returned LoggerV2 implements grpclog.DepthLoggerV2, so that the callers in gRPC are
+// reported as callers, if logger implements logging.CallerSkipper.
+//
+// The methods of the returned LoggerV2 are expected to be called by the grpclog functions,
+// e.g. grpclog.Info, rather than directly, as the frames of the grpclog functions are skipped.
+func NewLoggerV2(logger logging.Logger, verbosity int) grpclog.LoggerV2 {
+	// skip the methods of loggerV2, and the grpclog functions calling them.
+	return &loggerV2{logger: logging.AddCallerSkip(logger, 2), verbosity: verbosity}
+}
+
+var _ grpclog.DepthLoggerV2 = (*loggerV2)(nil)
+
+func (l *loggerV2) Info(args ...any)                 { l.logger.Info(args...) }
+func (l *loggerV2) Infoln(args ...any)               { l.logger.Infoln(args...) }
+func (l *loggerV2) Infof(format string, args ...any) { l.logger.Infof(format, args...) }
+func (l *loggerV2) Warning(args ...any)              { l.logger.Warn(args...) }
+func (l *loggerV2) Warningln(args ...any)            { l.logger.Warnln(args...) }
+func (l *loggerV2) Warningf(format string, args ...any) {
+	l.logger.Warnf(format, args...)
+}
+func (l *loggerV2) Error(args ...any)                 { l.logger.Error(args...) }
+func (l *loggerV2) Errorln(args ...any)               { l.logger.Errorln(args...) }
+func (l *loggerV2) Errorf(format string, args ...any) { l.logger.Errorf(format, args...) }
+func (l *loggerV2) Fatal(args ...any) {
+	l.logger.Error(args...)
+	exit(1)
+}
+func (l *loggerV2) Fatalln(args ...any) {
+	l.logger.Errorln(args...)
+	exit(1)
+}
+func (l *loggerV2) Fatalf(format string, args ...any) {
+	l.logger.Errorf(format, args...)
+	exit(1)
+}
+
+// V reports whether verbose logs at level are enabled.
+func (l *loggerV2) V(level int) bool { return level <= l.verbosity }
+
+// The depth methods are called by the grpclog depth functions, whose callers are at depth 0,
+// so the grpclog frames are skipped by the base skip as well.
+
+func (l *loggerV2) InfoDepth(depth int, args ...any) {
+	logging.AddCallerSkip(l.logger, depth).Infoln(args...)
+}
+func (l *loggerV2) WarningDepth(depth int, args ...any) {
+	logging.AddCallerSkip(l.logger, depth).Warnln(args...)
+}
+func (l *loggerV2) ErrorDepth(depth int, args ...any) {
+	logging.AddCallerSkip(l.logger, depth).Errorln(args...)
+}
+func (l *loggerV2) FatalDepth(depth int, args ...any) {
+	logging.AddCallerSkip(l.logger, depth).Errorln(args...)
+	exit(1)
+}
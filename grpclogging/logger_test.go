@@ -0,0 +1,108 @@
+package grpclogging
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/grpclog"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+// setLoggerV2 sets l as the grpclog LoggerV2 until the test ends.
+func setLoggerV2(t *testing.T, l grpclog.LoggerV2) {
+	grpclog.SetLoggerV2(l)
+	t.Cleanup(func() { grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, io.Discard, os.Stderr)) })
+}
+
+// fatal emulates the grpclog fatal functions, which exit the process after calling l.
+func fatal(l grpclog.LoggerV2) {
+	l.Fatal("f")
+	l.Fatalln("f")
+	l.Fatalf("f%d", 1)
+}
+
+func TestLoggerV2(t *testing.T) {
+	codes := 0
+	defer func(origin func(int)) { exit = origin }(exit)
+	exit = func(code int) { codes += code }
+
+	rec := logtest.New(logging.DebugLevel)
+	l := NewLoggerV2(rec, 2)
+	setLoggerV2(t, l)
+	grpclog.Info("a", 1)
+	grpclog.Infoln("a", 1)
+	grpclog.Infof("a%d", 1)
+	grpclog.Warning("w")
+	grpclog.Warningln("w")
+	grpclog.Warningf("w%d", 1)
+	grpclog.Error("e")
+	grpclog.Errorln("e")
+	grpclog.Errorf("e%d", 1)
+	fatal(l)
+	assert.True(t, grpclog.V(2))
+	assert.False(t, grpclog.V(3))
+	assert.Equal(t, 3, codes)
+
+	var got []string
+	for _, e := range rec.Entries() {
+		got = append(got, e.Level.String()+":"+e.Message)
+		assert.Equal(t, "github.com/yimi-go/logging/grpclogging.TestLoggerV2", e.Caller.Function)
+	}
+	info, warn, errs := logging.InfoLevel.String(), logging.WarnLevel.String(), logging.ErrorLevel.String()
+	assert.Equal(t, []string{
+		info + ":a1", info + ":a 1", info + ":a1",
+		warn + ":w", warn + ":w", warn + ":w1",
+		errs + ":e", errs + ":e", errs + ":e1",
+		errs + ":f", errs + ":f", errs + ":f1",
+	}, got)
+}
+
+// depthLog emulates the grpclog functions calling the depth methods.
+func depthLog(l grpclog.DepthLoggerV2, depth int) {
+	l.InfoDepth(depth, "i", 1)
+	l.WarningDepth(depth, "w")
+	l.ErrorDepth(depth, "e")
+	l.FatalDepth(depth, "f")
+}
+
+func helper(l grpclog.DepthLoggerV2) {
+	depthLog(l, 1)
+}
+
+func TestLoggerV2_component(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	setLoggerV2(t, NewLoggerV2(rec, 0))
+	c := grpclog.Component("test")
+	c.Info("i")
+	c.Warningf("w%d", 1)
+	c.Errorln("e")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 3)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/grpclogging.TestLoggerV2_component", e.Caller.Function)
+	}
+}
+
+func TestLoggerV2_depth(t *testing.T) {
+	codes := 0
+	defer func(origin func(int)) { exit = origin }(exit)
+	exit = func(code int) { codes += code }
+
+	rec := logtest.New(logging.DebugLevel)
+	l := NewLoggerV2(rec, 0).(grpclog.DepthLoggerV2)
+	depthLog(l, 0)
+	helper(l)
+	assert.Equal(t, 2, codes)
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 8)
+	assert.Equal(t, "i 1", entries[0].Message)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/grpclogging.TestLoggerV2_depth", e.Caller.Function)
+	}
+}
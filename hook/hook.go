@@ -116,6 +116,20 @@ func (l *hooked) WithField(field ...logging.Field) logging.Logger {
 	}
 }
 
+func (l *hooked) WithGroup(name string) logging.Logger {
+	return &hooked{
+		Logger: logging.WithGroup(l.Logger, name),
+		onLog:  l.onLog,
+	}
+}
+
+func (l *hooked) WithCallerSkip(skip int) logging.Logger {
+	return &hooked{
+		Logger: logging.AddCallerSkip(l.Logger, skip),
+		onLog:  l.onLog,
+	}
+}
+
 type hookedFactory struct {
 	factory logging.Factory
 	onLog   func(meth string, param ...any)
@@ -123,7 +137,8 @@ type hookedFactory struct {
 
 func (f *hookedFactory) Logger(name string) logging.Logger {
 	return &hooked{
-		Logger: f.factory.Logger(name),
+		// skip the hooked methods to report the real callers.
+		Logger: logging.AddCallerSkip(f.factory.Logger(name), 1),
 		onLog:  f.onLog,
 	}
 }
@@ -0,0 +1,242 @@
+// Package httplog logs the requests of net/http servers and clients via Loggers,
+// with the Fields built by netfield.
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/netfield"
+	"github.com/yimi-go/logging/semconv"
+)
+
+// The keys of the Fields logged by the middleware, besides the ones of netfield.
+const (
+	// RequestIDKey is the key of the request ID found in the request header.
+	RequestIDKey = "request.id"
+	// PanicKey is the key of the values recovered from panics.
+	PanicKey = "panic"
+	// StackKey is the key of the stacktraces of panics.
+	StackKey = "error.stack_trace"
+)
+
+// DefaultRequestIDHeader is the header of request IDs, unless set via WithRequestIDHeader.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// The messages of the logs of the middleware.
+const (
+	AccessMessage = "http request served"
+	PanicMessage  = "http handler panicked"
+)
+
+type options struct {
+	levelOf         func(status int, latency time.Duration) logging.Level
+	requestIDHeader string
+	slowWarn        time.Duration
+	slowError       time.Duration
+}
+
+// Option configures the middleware.
+type Option func(o *options)
+
+// WithLevels sets the function mapping the statuses and latencies to the Levels
+// the requests are logged at, replacing the default mapping. Requests are not logged at OffLevel.
+func WithLevels(levelOf func(status int, latency time.Duration) logging.Level) Option {
+	return func(o *options) {
+		o.levelOf = levelOf
+	}
+}
+
+// WithLatencyThresholds escalates the requests not faster than warn to WarnLevel,
+// and the ones not faster than fail to ErrorLevel. Zero thresholds are ignored.
+func WithLatencyThresholds(warn, fail time.Duration) Option {
+	return func(o *options) {
+		o.slowWarn, o.slowError = warn, fail
+	}
+}
+
+// WithRequestIDHeader sets the header of request IDs, DefaultRequestIDHeader by default.
+// An empty header disables request IDs.
+func WithRequestIDHeader(header string) Option {
+	return func(o *options) {
+		o.requestIDHeader = header
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{requestIDHeader: DefaultRequestIDHeader}
+	o.levelOf = o.defaultLevel
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// defaultLevel maps 5xx statuses to ErrorLevel, 4xx ones to WarnLevel and the others to InfoLevel,
+// escalated by the latency thresholds.
+func (o *options) defaultLevel(status int, latency time.Duration) logging.Level {
+	lvl := logging.InfoLevel
+	switch {
+	case status >= http.StatusInternalServerError:
+		lvl = logging.ErrorLevel
+	case status >= http.StatusBadRequest:
+		lvl = logging.WarnLevel
+	}
+	switch {
+	case o.slowError > 0 && latency >= o.slowError:
+		lvl = logging.ErrorLevel
+	case o.slowWarn > 0 && latency >= o.slowWarn && lvl < logging.WarnLevel:
+		lvl = logging.WarnLevel
+	}
+	return lvl
+}
+
+// Middleware returns a middleware logging an access entry per request via logger.
+//
+// The Fields of the requests built by netfield.HTTPRequest, and the request IDs keyed by
+// RequestIDKey, are put into the request contexts via logging.NewContext, so that handlers
+// could log with them via logging.WithContextField. The access entries have the Fields
+// in the contexts, and the ones built by netfield.HTTPResponse, whose "http" Groups
+// are merged with the ones of the requests via semconv.Nest.
+//
+// Panics of the handlers are recovered and logged at ErrorLevel with the stacktraces,
+// and the requests are responded with 500 if nothing has been written.
+// http.ErrAbortHandler is re-panicked after logged the access entries, as net/http expects.
+func Middleware(logger logging.Logger, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			schema := semconv.GetSchema()
+			request := netfield.HTTPRequestIn(schema, r)
+			fields := request
+			if o.requestIDHeader != "" {
+				if id := r.Header.Get(o.requestIDHeader); id != "" {
+					fields = append(fields[:len(fields):len(fields)], logging.String(RequestIDKey, id))
+				}
+			}
+			r = r.WithContext(logging.NewContext(r.Context(), fields...))
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v != nil && v != http.ErrAbortHandler {
+					logging.WithContextField(r.Context(), logger).
+						Errorw(PanicMessage, logging.Any(PanicKey, v), logging.Stack(StackKey))
+					if !rw.wroteHeader {
+						rw.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+				// the request Fields are logged merged with the response ones instead.
+				ctx := logging.NewContextWithout(r.Context(), netfield.HTTPRequestKeys(schema)...)
+				o.log(logging.WithContextField(ctx, logger), schema, request, rw, time.Since(start))
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// log logs the access entry of request via logger at the Level of the status and latency.
+func (o *options) log(logger logging.Logger, schema semconv.Schema, request []logging.Field,
+	rw *responseWriter, latency time.Duration) {
+	status := rw.status
+	if !rw.wroteHeader {
+		status = http.StatusOK
+	}
+	lvl := o.levelOf(status, latency)
+	if lvl == logging.OffLevel || !logger.Enabled(lvl) {
+		return
+	}
+	fields := semconv.Nest(append(request[:len(request):len(request)],
+		netfield.HTTPResponseIn(schema, status, rw.size, latency)...)...)
+	switch lvl {
+	case logging.DebugLevel:
+		logger.Debugw(AccessMessage, fields...)
+	case logging.InfoLevel:
+		logger.Infow(AccessMessage, fields...)
+	case logging.WarnLevel:
+		logger.Warnw(AccessMessage, fields...)
+	default:
+		logger.Errorw(AccessMessage, fields...)
+	}
+}
+
+// responseWriter records the status and the size of the body written.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		// 1xx informational headers could be followed by the final ones.
+		w.wroteHeader = status >= http.StatusOK || status == http.StatusSwitchingProtocols
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush flushes the underlying ResponseWriter, if it is an http.Flusher.
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the underlying ResponseWriter, if it is an http.Hijacker.
+// The hijacked responses are logged with status 101 if no status has been written.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// ReadFrom copies src via the underlying ResponseWriter, if it is an io.ReaderFrom,
+// e.g. for sendfile, counting the bytes written.
+func (w *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		// hide ReadFrom of w, or io.Copy would call it again.
+		n, err = io.Copy(struct{ io.Writer }{w.ResponseWriter}, src)
+	}
+	w.size += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
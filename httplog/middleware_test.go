@@ -0,0 +1,254 @@
+package httplog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+// value returns the value of the Field keyed by key in fields,
+// or nested in Groups by the dotted segments of key, e.g. "http.request.method".
+func value(fields []logging.Field, key string) any {
+	for _, f := range fields {
+		if f.Key() == key {
+			return f.Value()
+		}
+	}
+	for _, f := range fields {
+		if rest, ok := strings.CutPrefix(key, f.Key()+"."); ok && f.Type() == logging.GroupType {
+			if v := value(f.Value().([]logging.Field), rest); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// count returns the number of the Fields keyed by key in fields.
+func count(fields []logging.Field, key string) int {
+	n := 0
+	for _, f := range fields {
+		if f.Key() == key {
+			n++
+		}
+	}
+	return n
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	var ctxFields logging.FieldSet
+	h := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxFields = logging.FieldsFromContext(r.Context())
+		logging.WithContextField(r.Context(), rec).Infow("handling")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set(DefaultRequestIDHeader, "req-1")
+	w := serve(h, r)
+	assert.Equal(t, "hello", w.Body.String())
+
+	id, ok := ctxFields.Get(RequestIDKey)
+	assert.True(t, ok)
+	assert.Equal(t, "req-1", id.StringValue())
+	assert.Equal(t, "GET", value(ctxFields.Fields(), "http.request.method"))
+
+	entries := rec.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "req-1", value(entries[0].Fields, RequestIDKey))
+	access := entries[1]
+	assert.Equal(t, AccessMessage, access.Message)
+	assert.Equal(t, logging.InfoLevel, access.Level)
+	assert.Equal(t, "req-1", value(access.Fields, RequestIDKey))
+	assert.Equal(t, "/users/1", value(access.Fields, "url.path"))
+	assert.Equal(t, int64(200), value(access.Fields, "http.response.status_code"))
+	assert.Equal(t, int64(5), value(access.Fields, "http.response.body.size"))
+	assert.NotNil(t, value(access.Fields, "http.server.request.duration"))
+	assert.Equal(t, "GET", value(access.Fields, "http.request.method"))
+	assert.Equal(t, 1, count(access.Fields, "http"), "the request and response Groups are merged")
+}
+
+func TestMiddleware_status(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := Middleware(rec, WithRequestIDHeader(""))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultRequestIDHeader, "req-1")
+	serve(h, r)
+	serve(h, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	serve(h, httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+	entries := rec.Entries()
+	require.Len(t, entries, 3)
+	assert.Nil(t, value(entries[0].Fields, RequestIDKey))
+	assert.Equal(t, int64(200), value(entries[0].Fields, "http.response.status_code"))
+	assert.Equal(t, logging.InfoLevel, entries[0].Level)
+	assert.Equal(t, int64(404), value(entries[1].Fields, "http.response.status_code"))
+	assert.Equal(t, logging.WarnLevel, entries[1].Level)
+	assert.Equal(t, int64(502), value(entries[2].Fields, "http.response.status_code"))
+	assert.Equal(t, logging.ErrorLevel, entries[2].Level)
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	entries := rec.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, PanicMessage, entries[0].Message)
+	assert.Equal(t, logging.ErrorLevel, entries[0].Level)
+	assert.Equal(t, "boom", value(entries[0].Fields, PanicKey))
+	assert.Contains(t, value(entries[0].Fields, StackKey).(*logging.Stacktrace).String(), "TestMiddleware_panic")
+	assert.Equal(t, "GET", value(entries[0].Fields, "http.request.method"))
+	assert.Equal(t, int64(500), value(entries[1].Fields, "http.response.status_code"))
+	assert.Equal(t, logging.ErrorLevel, entries[1].Level)
+}
+
+func TestMiddleware_panicAfterWrite(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("boom")
+	}))
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	entries := rec.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(202), value(entries[1].Fields, "http.response.status_code"))
+}
+
+func TestMiddleware_abortHandler(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, AccessMessage, entries[0].Message)
+}
+
+func TestMiddleware_levels(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := Middleware(rec, WithLevels(func(status int, _ time.Duration) logging.Level {
+		if status == http.StatusOK {
+			return logging.OffLevel
+		}
+		return logging.DebugLevel
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	serve(h, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logging.DebugLevel, entries[0].Level)
+}
+
+func TestDefaultLevel(t *testing.T) {
+	o := newOptions(nil)
+	assert.Equal(t, logging.InfoLevel, o.levelOf(200, time.Hour))
+	assert.Equal(t, logging.InfoLevel, o.levelOf(304, 0))
+	assert.Equal(t, logging.WarnLevel, o.levelOf(400, 0))
+	assert.Equal(t, logging.ErrorLevel, o.levelOf(503, 0))
+
+	o = newOptions([]Option{WithLatencyThresholds(time.Second, time.Minute)})
+	assert.Equal(t, logging.InfoLevel, o.levelOf(200, time.Millisecond))
+	assert.Equal(t, logging.WarnLevel, o.levelOf(200, time.Second))
+	assert.Equal(t, logging.WarnLevel, o.levelOf(404, time.Second))
+	assert.Equal(t, logging.ErrorLevel, o.levelOf(200, time.Minute))
+	assert.Equal(t, logging.ErrorLevel, o.levelOf(500, time.Second))
+}
+
+func TestResponseWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: w}
+	rw.WriteHeader(http.StatusContinue)
+	assert.False(t, rw.wroteHeader)
+	rw.Flush()
+	assert.True(t, rw.wroteHeader)
+	assert.Equal(t, http.StatusOK, rw.status)
+	assert.True(t, w.Flushed)
+	assert.Same(t, w, rw.Unwrap())
+}
+
+func TestResponseWriter_readFrom(t *testing.T) {
+	w := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: w}
+	n, err := rw.ReadFrom(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, int64(5), rw.size)
+	assert.Equal(t, http.StatusOK, rw.status)
+	assert.Equal(t, "hello", w.Body.String())
+
+	_, _, err = rw.Hijack()
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+}
+
+func TestMiddleware_passThrough(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	served := make(chan struct{}, 1)
+	h := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hijack" {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nhi")
+			_ = buf.Flush()
+			_ = conn.Close()
+			return
+		}
+		_, ok := w.(io.ReaderFrom)
+		assert.True(t, ok)
+		_, _ = io.Copy(w, strings.NewReader("copied"))
+	}))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		served <- struct{}{}
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/copy", "/hijack"} {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.NotEmpty(t, body)
+		<-served
+	}
+	entries := rec.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(6), value(entries[0].Fields, "http.response.body.size"))
+	assert.Equal(t, int64(200), value(entries[0].Fields, "http.response.status_code"))
+	assert.Equal(t, int64(101), value(entries[1].Fields, "http.response.status_code"))
+}
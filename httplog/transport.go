@@ -0,0 +1,308 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/netfield"
+	"github.com/yimi-go/logging/redact"
+	"github.com/yimi-go/logging/semconv"
+)
+
+// The keys of the Fields logged by the Transport, besides the ones of semconv.
+// The Fields are nested by the dotted segments of the keys, see semconv.Nest.
+const (
+	// RequestHeaderKey is the key of the Group of the request header captured.
+	RequestHeaderKey = "http.request.header"
+	// ResponseHeaderKey is the key of the Group of the response header captured.
+	ResponseHeaderKey = "http.response.header"
+	// RequestBodyKey is the key of the request body captured.
+	RequestBodyKey = "http.request.body.content"
+	// ResponseBodyKey is the key of the response body captured.
+	ResponseBodyKey = "http.response.body.content"
+	// RequestBodyErrorKey is the key of the error reading the request body to capture.
+	RequestBodyErrorKey = "http.request.body.error"
+	// ResponseBodyErrorKey is the key of the error reading the response body to capture.
+	ResponseBodyErrorKey = "http.response.body.error"
+)
+
+// TraceParentHeader is the W3C trace context header propagated by the Transport.
+const TraceParentHeader = "Traceparent"
+
+// ClientMessage is the message of the logs of the Transport.
+const ClientMessage = "http request sent"
+
+// DefaultBodyRedactor masks the bearer tokens, JWTs, credit card numbers and email addresses
+// in the bodies captured, unless a Redactor is given to WithBodies.
+var DefaultBodyRedactor = redact.New(
+	redact.Values(redact.Mask, redact.BearerToken, redact.JWT, redact.CreditCard, redact.Email),
+)
+
+type retryAttemptKey struct{}
+
+// ContextWithRetryAttempt returns a context carrying the retry attempt of the request,
+// 0 for the first one, for the Transport to log. Retrying clients should call it.
+func ContextWithRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+// RetryAttempt returns the retry attempt set via ContextWithRetryAttempt, or 0 if not set.
+func RetryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
+type transportOptions struct {
+	levelOf   func(status int, err error) logging.Level
+	redactor  *redact.Redactor
+	sensitive []string
+	bodyLimit int
+	headers   bool
+}
+
+// TransportOption configures the Transport.
+type TransportOption func(o *transportOptions)
+
+// WithTransportLevels sets the function mapping the statuses and errors to the Levels
+// the requests are logged at, DefaultTransportLevel by default. Requests are not logged at OffLevel.
+// The status is 0 if err is not nil.
+func WithTransportLevels(levelOf func(status int, err error) logging.Level) TransportOption {
+	return func(o *transportOptions) {
+		o.levelOf = levelOf
+	}
+}
+
+// WithHeaders captures the request and response headers via netfield.Header,
+// with the values of the default sensitive headers and of sensitive masked.
+func WithHeaders(sensitive ...string) TransportOption {
+	return func(o *transportOptions) {
+		o.headers = true
+		o.sensitive = sensitive
+	}
+}
+
+// WithBodies captures the first limit bytes of the request and response bodies,
+// redacted by redactor, or DefaultBodyRedactor if it is nil.
+//
+// The response bodies are read up to limit before RoundTrip returns,
+// so it should not be used for the streaming responses.
+// The errors reading the bodies are logged, and returned by the bodies after the bytes read.
+func WithBodies(limit int, redactor *redact.Redactor) TransportOption {
+	return func(o *transportOptions) {
+		o.bodyLimit = limit
+		o.redactor = redactor
+	}
+}
+
+// DefaultTransportLevel maps the failed requests and 5xx statuses to ErrorLevel,
+// 4xx ones to WarnLevel and the others to InfoLevel.
+func DefaultTransportLevel(status int, err error) logging.Level {
+	switch {
+	case err != nil || status >= http.StatusInternalServerError:
+		return logging.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return logging.WarnLevel
+	default:
+		return logging.InfoLevel
+	}
+}
+
+type transport struct {
+	next   http.RoundTripper
+	logger logging.Logger
+	opts   *transportOptions
+}
+
+// NewTransport returns an http.RoundTripper logging the requests sent via next,
+// or http.DefaultTransport if next is nil, via logger.
+//
+// The logs have the Fields in the request contexts, the method, host, route set via
+// netfield.ContextWithRoute, retry attempt set via ContextWithRetryAttempt, status and duration
+// until the response header is received, keyed by the semconv Keys in the Schema set via
+// semconv.SwapSchema, e.g. semconv.ServerAddress and semconv.HTTPClientDuration,
+// and nested by their names via semconv.Nest, as netfield does.
+// The retry attempts are omitted in the Schemas not defining semconv.HTTPRequestResendCount.
+// The Fields of inbound requests in the contexts, i.e. the Groups keyed by netfield.HTTPRequestKeys
+// as put by Middleware, are dropped, so that they are not mistaken for the ones of the requests sent.
+//
+// The request IDs keyed by RequestIDKey, and the trace contexts keyed by semconv.TraceID and
+// semconv.SpanID, in the request context Fields are propagated via the DefaultRequestIDHeader
+// and TraceParentHeader headers, unless the requests have them already.
+// The requests are cloned rather than modified.
+func NewTransport(logger logging.Logger, next http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	o := &transportOptions{levelOf: DefaultTransportLevel}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.redactor == nil {
+		o.redactor = DefaultBodyRedactor
+	}
+	return &transport{next: next, logger: logger, opts: o}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	out := propagate(req)
+	var reqBody []byte
+	var reqBodyErr error
+	if t.opts.bodyLimit > 0 && req.Body != nil && req.Body != http.NoBody {
+		if out == req {
+			out = req.Clone(req.Context())
+		}
+		reqBody, out.Body, reqBodyErr = peekBody(req.Body, t.opts.bodyLimit)
+	}
+	resp, err := t.next.RoundTrip(out)
+	duration := time.Since(start)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	lvl := t.opts.levelOf(status, err)
+	schema := semconv.GetSchema()
+	ctx := logging.NewContextWithout(req.Context(), netfield.HTTPRequestKeys(schema)...)
+	logger := logging.WithContextField(ctx, t.logger)
+	if lvl == logging.OffLevel || !logger.Enabled(lvl) {
+		return resp, err
+	}
+	fields := t.requestFields(schema, req, reqBody)
+	if reqBodyErr != nil {
+		fields = append(fields, logging.NamedError(RequestBodyErrorKey, reqBodyErr))
+	}
+	if err != nil {
+		fields = append(fields, logging.Error(err))
+	} else {
+		fields = append(fields, logging.Int(semconv.HTTPResponseStatusCode.Name(schema), status))
+		if t.opts.headers {
+			fields = append(fields, netfield.Header(ResponseHeaderKey, resp.Header, t.opts.sensitive...))
+		}
+		if t.opts.bodyLimit > 0 && resp.Body != nil && resp.Body != http.NoBody {
+			body, replay, bodyErr := peekBody(resp.Body, t.opts.bodyLimit)
+			resp.Body = replay
+			fields = append(fields, logging.String(ResponseBodyKey, t.opts.redactor.String(string(body))))
+			if bodyErr != nil {
+				fields = append(fields, logging.NamedError(ResponseBodyErrorKey, bodyErr))
+			}
+		}
+	}
+	if semconv.HTTPClientDuration.Kind(schema) == semconv.IntKind {
+		fields = append(fields, logging.Int64(semconv.HTTPClientDuration.Name(schema), duration.Nanoseconds()))
+	} else {
+		fields = append(fields, logging.Float64(semconv.HTTPClientDuration.Name(schema), duration.Seconds()))
+	}
+	fields = semconv.Nest(fields...)
+	switch lvl {
+	case logging.DebugLevel:
+		logger.Debugw(ClientMessage, fields...)
+	case logging.InfoLevel:
+		logger.Infow(ClientMessage, fields...)
+	case logging.WarnLevel:
+		logger.Warnw(ClientMessage, fields...)
+	default:
+		logger.Errorw(ClientMessage, fields...)
+	}
+	return resp, err
+}
+
+// requestFields returns the Fields of req in schema, with the captured body.
+func (t *transport) requestFields(schema semconv.Schema, req *http.Request, body []byte) []logging.Field {
+	fields := make([]logging.Field, 0, 10)
+	fields = append(fields,
+		logging.String(semconv.HTTPRequestMethod.Name(schema), req.Method),
+		logging.String(semconv.ServerAddress.Name(schema), req.URL.Host),
+	)
+	if route := netfield.Route(req.Context()); route != "" {
+		fields = append(fields, logging.String(semconv.HTTPRoute.Name(schema), route))
+	}
+	resendCount := semconv.HTTPRequestResendCount.Name(schema)
+	if attempt := RetryAttempt(req.Context()); attempt > 0 && resendCount != "" {
+		fields = append(fields, logging.Int(resendCount, attempt))
+	}
+	if t.opts.headers {
+		fields = append(fields, netfield.Header(RequestHeaderKey, req.Header, t.opts.sensitive...))
+	}
+	if body != nil {
+		fields = append(fields, logging.String(RequestBodyKey, t.opts.redactor.String(string(body))))
+	}
+	return fields
+}
+
+// propagate returns a clone of req with the request ID and trace context in the context Fields
+// set to the headers missing, or req itself if nothing to propagate.
+func propagate(req *http.Request) *http.Request {
+	set := logging.FieldsFromContext(req.Context())
+	if set.Len() == 0 {
+		return req
+	}
+	header := make(map[string]string, 2)
+	if req.Header.Get(DefaultRequestIDHeader) == "" {
+		if f, ok := set.Get(RequestIDKey); ok && f.Type() == logging.StringType && f.StringValue() != "" {
+			header[DefaultRequestIDHeader] = f.StringValue()
+		}
+	}
+	if req.Header.Get(TraceParentHeader) == "" {
+		traceID, ok1 := set.Get(semconv.TraceID.String())
+		spanID, ok2 := set.Get(semconv.SpanID.String())
+		if ok1 && ok2 && isHex(traceID.StringValue(), 32) && isHex(spanID.StringValue(), 16) {
+			header[TraceParentHeader] = "00-" + traceID.StringValue() + "-" + spanID.StringValue() + "-01"
+		}
+	}
+	if len(header) == 0 {
+		return req
+	}
+	out := req.Clone(req.Context())
+	for k, v := range header {
+		out.Header.Set(k, v)
+	}
+	return out
+}
+
+// isHex reports whether s is a non-zero lowercase hex string of length n.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	zero := true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+		zero = zero && c == '0'
+	}
+	return !zero
+}
+
+// peekBody reads the first limit bytes of body, and returns them with a body replaying them.
+// If reading fails, the bytes read and the error are returned,
+// and the body replaying returns the error after the bytes read.
+func peekBody(body io.ReadCloser, limit int) ([]byte, io.ReadCloser, error) {
+	peeked, err := io.ReadAll(io.LimitReader(body, int64(limit)))
+	rest := io.Reader(body)
+	if err != nil {
+		rest = &errReader{err: err}
+	}
+	return peeked, &replayBody{Reader: io.MultiReader(bytes.NewReader(peeked), rest), Closer: body}, err
+}
+
+// errReader always fails with err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// replayBody reads the peeked bytes before the rest of the origin body, and closes the origin one.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,312 @@
+package httplog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+	"github.com/yimi-go/logging/netfield"
+	"github.com/yimi-go/logging/redact"
+	"github.com/yimi-go/logging/semconv"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *[]*http.Request) {
+	var received []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		received = append(received, r)
+		w.Header().Set("Set-Cookie", "secret")
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			_, _ = w.Write([]byte("reply with token Bearer abc.def and " + string(body)))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &received
+}
+
+func TestTransport(t *testing.T) {
+	srv, received := newServer(t)
+	rec := logtest.New(logging.DebugLevel)
+	client := &http.Client{Transport: NewTransport(rec, nil)}
+
+	ctx := netfield.ContextWithRoute(context.Background(), "/users/{id}")
+	ctx = ContextWithRetryAttempt(ctx, 2)
+	ctx = logging.NewContext(ctx,
+		logging.String(RequestIDKey, "req-1"),
+		logging.String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		logging.String("span_id", "00f067aa0ba902b7"),
+	)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/users/1", nil)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Empty(t, req.Header, "the request is not modified")
+
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/missing", nil)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Len(t, *received, 2)
+	assert.Equal(t, "req-1", (*received)[0].Header.Get(DefaultRequestIDHeader))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		(*received)[0].Header.Get(TraceParentHeader))
+	assert.Empty(t, (*received)[1].Header.Get(DefaultRequestIDHeader))
+
+	entries := rec.Entries()
+	require.Len(t, entries, 2)
+	e := entries[0]
+	assert.Equal(t, ClientMessage, e.Message)
+	assert.Equal(t, logging.InfoLevel, e.Level)
+	assert.Equal(t, "req-1", value(e.Fields, RequestIDKey))
+	assert.Equal(t, "GET", value(e.Fields, "http.request.method"))
+	assert.Equal(t, strings.TrimPrefix(srv.URL, "http://"), value(e.Fields, "server.address"))
+	assert.Equal(t, "/users/{id}", value(e.Fields, "http.route"))
+	assert.Equal(t, int64(2), value(e.Fields, "http.request.resend_count"))
+	assert.Equal(t, int64(200), value(e.Fields, "http.response.status_code"))
+	assert.NotNil(t, value(e.Fields, "http.client.request.duration"))
+	assert.Nil(t, value(e.Fields, RequestHeaderKey))
+	assert.Nil(t, value(e.Fields, ResponseBodyKey))
+
+	e = entries[1]
+	assert.Equal(t, logging.WarnLevel, e.Level)
+	assert.Nil(t, value(e.Fields, "http.route"))
+	assert.Nil(t, value(e.Fields, "http.request.resend_count"))
+	assert.Equal(t, int64(404), value(e.Fields, "http.response.status_code"))
+}
+
+func TestTransport_capture(t *testing.T) {
+	srv, received := newServer(t)
+	rec := logtest.New(logging.DebugLevel)
+	client := &http.Client{Transport: NewTransport(rec, http.DefaultTransport,
+		WithHeaders("X-Secret"), WithBodies(16, nil))}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("mail me at a@example.com"))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("X-Secret", "s")
+	req.Header.Set("Accept", "text/plain")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "reply with token Bearer abc.def and mail me at a@example.com", string(body))
+
+	body, _ = io.ReadAll((*received)[0].Body)
+	assert.Equal(t, "mail me at a@example.com", string(body))
+
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, "mail me at a@exa", value(e.Fields, RequestBodyKey))
+	assert.Equal(t, "reply with token", value(e.Fields, ResponseBodyKey))
+	reqHeader := value(e.Fields, RequestHeaderKey).([]logging.Field)
+	assert.Equal(t, logging.RedactedValue, value(reqHeader, "Authorization"))
+	assert.Equal(t, logging.RedactedValue, value(reqHeader, "X-Secret"))
+	assert.Equal(t, "text/plain", value(reqHeader, "Accept"))
+	respHeader := value(e.Fields, ResponseHeaderKey).([]logging.Field)
+	assert.Equal(t, logging.RedactedValue, value(respHeader, "Set-Cookie"))
+}
+
+func TestTransport_redactor(t *testing.T) {
+	srv, _ := newServer(t)
+	rec := logtest.New(logging.DebugLevel)
+	client := &http.Client{Transport: NewTransport(rec, nil, WithBodies(64, nil))}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("a@example.com"))
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	e := rec.Entries()[0]
+	assert.Equal(t, logging.RedactedValue, value(e.Fields, RequestBodyKey))
+	assert.Equal(t, "reply with token Bearer "+logging.RedactedValue+" and "+logging.RedactedValue,
+		value(e.Fields, ResponseBodyKey))
+
+	rec = logtest.New(logging.DebugLevel)
+	client = &http.Client{Transport: NewTransport(rec, nil, WithBodies(64, redact.New()))}
+	req, _ = http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("a@example.com"))
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "a@example.com", value(rec.Entries()[0].Fields, RequestBodyKey))
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("refused")
+}
+
+func TestTransport_error(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	tr := NewTransport(rec, failingTransport{})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	_, err := tr.RoundTrip(req)
+	assert.Error(t, err)
+
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logging.ErrorLevel, entries[0].Level)
+	assert.Nil(t, value(entries[0].Fields, "http.response.status_code"))
+	assert.NotNil(t, value(entries[0].Fields, "error"))
+}
+
+func TestTransport_ECS(t *testing.T) {
+	defer semconv.SwapSchema(semconv.SwapSchema(semconv.ECS))
+	srv, _ := newServer(t)
+	rec := logtest.New(logging.DebugLevel)
+	client := &http.Client{Transport: NewTransport(rec, nil)}
+	req, _ := http.NewRequestWithContext(ContextWithRetryAttempt(context.Background(), 1),
+		http.MethodGet, srv.URL, nil)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	entries := rec.Entries()
+	require.Len(t, entries, 1)
+	fields := entries[0].Fields
+	assert.Equal(t, strings.TrimPrefix(srv.URL, "http://"), value(fields, "destination.address"))
+	assert.IsType(t, int64(0), value(fields, "event.duration"))
+	assert.Nil(t, value(fields, "http.request.resend_count"))
+	var violations []semconv.Violation
+	semconv.NewValidator(semconv.ECS, semconv.Warn).Fields(fields,
+		func(v semconv.Violation) { violations = append(violations, v) })
+	assert.Empty(t, violations)
+}
+
+func TestTransport_levels(t *testing.T) {
+	srv, _ := newServer(t)
+	rec := logtest.New(logging.InfoLevel)
+	tr := NewTransport(rec, nil, WithTransportLevels(func(status int, err error) logging.Level {
+		if status == http.StatusOK {
+			return logging.DebugLevel
+		}
+		return logging.OffLevel
+	}), WithBodies(4, nil))
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "reply with token Bearer abc.def and ", string(body))
+	assert.Empty(t, rec.Entries())
+}
+
+func TestTransport_afterMiddleware(t *testing.T) {
+	backend, received := newServer(t)
+	clientLogs := logtest.New(logging.DebugLevel)
+	client := &http.Client{Transport: NewTransport(clientLogs, nil)}
+	serverLogs := logtest.New(logging.DebugLevel)
+	h := Middleware(serverLogs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodPost, backend.URL+"/users/1", nil)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/front", nil)
+	r.Header.Set(DefaultRequestIDHeader, "req-1")
+	r.Header.Set("User-Agent", "inbound")
+	serve(h, r)
+
+	assert.Equal(t, "req-1", (*received)[0].Header.Get(DefaultRequestIDHeader))
+	entries := clientLogs.Entries()
+	require.Len(t, entries, 1)
+	fields := entries[0].Fields
+	assert.Equal(t, "req-1", value(fields, RequestIDKey))
+	assert.Equal(t, 1, count(fields, "http"))
+	assert.Equal(t, "POST", value(fields, "http.request.method"))
+	assert.Nil(t, value(fields, "url.path"))
+	assert.Nil(t, value(fields, "user_agent.original"))
+	assert.Nil(t, value(fields, "client.address"))
+
+	access := serverLogs.Entries()[0]
+	assert.Equal(t, "GET", value(access.Fields, "http.request.method"))
+	assert.Equal(t, "inbound", value(access.Fields, "user_agent.original"))
+}
+
+type failingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestPeekBody_error(t *testing.T) {
+	errRead := errors.New("read failed")
+	body := &failingBody{Reader: io.MultiReader(strings.NewReader("abc"), &errReader{err: errRead})}
+	peeked, replay, err := peekBody(body, 16)
+	assert.Equal(t, "abc", string(peeked))
+	assert.Same(t, errRead, err)
+	got, err := io.ReadAll(replay)
+	assert.Equal(t, "abc", string(got))
+	assert.Same(t, errRead, err)
+	assert.NoError(t, replay.Close())
+	assert.True(t, body.closed)
+}
+
+type failingBodyTransport struct{}
+
+func (failingBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       &failingBody{Reader: io.MultiReader(strings.NewReader("ok"), &errReader{err: io.ErrUnexpectedEOF})},
+		Request:    req,
+	}, nil
+}
+
+func TestTransport_bodyError(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	tr := NewTransport(rec, failingBodyTransport{}, WithBodies(16, nil))
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/",
+		&failingBody{Reader: &errReader{err: io.ErrClosedPipe}})
+	resp, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	assert.Same(t, io.ErrUnexpectedEOF, err)
+
+	fields := rec.Entries()[0].Fields
+	assert.Same(t, io.ErrClosedPipe, value(fields, RequestBodyErrorKey))
+	assert.Same(t, io.ErrUnexpectedEOF, value(fields, ResponseBodyErrorKey))
+	assert.Equal(t, "ok", value(fields, ResponseBodyKey))
+}
+
+func TestDefaultTransportLevel(t *testing.T) {
+	assert.Equal(t, logging.InfoLevel, DefaultTransportLevel(200, nil))
+	assert.Equal(t, logging.WarnLevel, DefaultTransportLevel(429, nil))
+	assert.Equal(t, logging.ErrorLevel, DefaultTransportLevel(502, nil))
+	assert.Equal(t, logging.ErrorLevel, DefaultTransportLevel(0, errors.New("e")))
+}
+
+func TestPropagate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Same(t, req, propagate(req))
+
+	ctx := logging.NewContext(context.Background(),
+		logging.String(RequestIDKey, "req-1"),
+		logging.String("trace_id", "00000000000000000000000000000000"),
+		logging.String("span_id", "00f067aa0ba902b7"),
+	)
+	req = req.WithContext(ctx)
+	req.Header.Set(DefaultRequestIDHeader, "req-0")
+	assert.Same(t, req, propagate(req))
+
+	assert.False(t, isHex("4BF92F3577B34DA6A3CE929D0E0E4736", 32))
+	assert.False(t, isHex("abc", 32))
+	assert.True(t, isHex("00f067aa0ba902b7", 16))
+	assert.Equal(t, 0, RetryAttempt(ctx))
+}
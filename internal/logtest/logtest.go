@@ -0,0 +1,111 @@
+// Package logtest provides a Logger and Factory that record logs in memory, for testing.
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yimi-go/logging"
+)
+
+// Entry is a recorded log.
+type Entry struct {
+	Caller  logging.Caller
+	Name    string
+	Message string
+	Fields  []logging.Field
+	Level   logging.Level
+}
+
+type entries struct {
+	list []Entry
+	mu   sync.Mutex
+}
+
+// Recorder is a Logger and Factory that records logs at or above its Level.
+type Recorder struct {
+	entries *entries
+	name    string
+	fields  []logging.Field
+	skip    int
+	level   logging.Level
+}
+
+// New creates a Recorder that records logs at or above level.
+func New(level logging.Level) *Recorder {
+	return &Recorder{entries: &entries{}, level: level}
+}
+
+// Entries returns all the recorded logs, including the ones of derived Loggers.
+func (r *Recorder) Entries() []Entry {
+	r.entries.mu.Lock()
+	defer r.entries.mu.Unlock()
+	list := make([]Entry, len(r.entries.list))
+	copy(list, r.entries.list)
+	return list
+}
+
+// Logger returns a Recorder sharing the recorded logs, with the given name.
+func (r *Recorder) Logger(name string) logging.Logger {
+	return &Recorder{entries: r.entries, name: name, level: r.level}
+}
+
+func (r *Recorder) record(lvl logging.Level, msg string, fields []logging.Field) {
+	if !r.Enabled(lvl) {
+		return
+	}
+	// skip record and the logging method.
+	caller := logging.CaptureCaller(2 + r.skip)
+	all := make([]logging.Field, 0, len(r.fields)+len(fields))
+	all = append(all, r.fields...)
+	all = append(all, fields...)
+	r.entries.mu.Lock()
+	defer r.entries.mu.Unlock()
+	r.entries.list = append(r.entries.list, Entry{Caller: caller, Name: r.name, Message: msg, Fields: all, Level: lvl})
+}
+
+func sprintln(v []any) string { return strings.TrimSuffix(fmt.Sprintln(v...), "\n") }
+
+func (r *Recorder) Enabled(lvl logging.Level) bool { return r.level.Enabled(lvl) }
+func (r *Recorder) Debug(v ...any)                 { r.record(logging.DebugLevel, fmt.Sprint(v...), nil) }
+func (r *Recorder) Debugln(v ...any)               { r.record(logging.DebugLevel, sprintln(v), nil) }
+func (r *Recorder) Debugf(format string, v ...any) {
+	r.record(logging.DebugLevel, fmt.Sprintf(format, v...), nil)
+}
+func (r *Recorder) Debugw(message string, field ...logging.Field) {
+	r.record(logging.DebugLevel, message, field)
+}
+func (r *Recorder) Info(v ...any)   { r.record(logging.InfoLevel, fmt.Sprint(v...), nil) }
+func (r *Recorder) Infoln(v ...any) { r.record(logging.InfoLevel, sprintln(v), nil) }
+func (r *Recorder) Infof(format string, v ...any) {
+	r.record(logging.InfoLevel, fmt.Sprintf(format, v...), nil)
+}
+func (r *Recorder) Infow(message string, field ...logging.Field) {
+	r.record(logging.InfoLevel, message, field)
+}
+func (r *Recorder) Warn(v ...any)   { r.record(logging.WarnLevel, fmt.Sprint(v...), nil) }
+func (r *Recorder) Warnln(v ...any) { r.record(logging.WarnLevel, sprintln(v), nil) }
+func (r *Recorder) Warnf(format string, v ...any) {
+	r.record(logging.WarnLevel, fmt.Sprintf(format, v...), nil)
+}
+func (r *Recorder) Warnw(message string, field ...logging.Field) {
+	r.record(logging.WarnLevel, message, field)
+}
+func (r *Recorder) Error(v ...any)   { r.record(logging.ErrorLevel, fmt.Sprint(v...), nil) }
+func (r *Recorder) Errorln(v ...any) { r.record(logging.ErrorLevel, sprintln(v), nil) }
+func (r *Recorder) Errorf(format string, v ...any) {
+	r.record(logging.ErrorLevel, fmt.Sprintf(format, v...), nil)
+}
+func (r *Recorder) Errorw(message string, field ...logging.Field) {
+	r.record(logging.ErrorLevel, message, field)
+}
+func (r *Recorder) WithField(field ...logging.Field) logging.Logger {
+	fields := make([]logging.Field, 0, len(r.fields)+len(field))
+	fields = append(fields, r.fields...)
+	fields = append(fields, field...)
+	return &Recorder{entries: r.entries, name: r.name, fields: fields, skip: r.skip, level: r.level}
+}
+func (r *Recorder) WithCallerSkip(skip int) logging.Logger {
+	return &Recorder{entries: r.entries, name: r.name, fields: r.fields, skip: r.skip + skip, level: r.level}
+}
@@ -0,0 +1,87 @@
+package rewrite
+
+import (
+	"errors"
+
+	"github.com/yimi-go/logging"
+)
+
+// maxErrorDepth limits how deep Error walks into error trees, as the logging error helpers do.
+const maxErrorDepth = 32
+
+// Error rewrites the messages of err and the errors it wraps via message,
+// and the Fields they carry via fields, if fields is not nil.
+// It returns the rewritten error and whether err is replaced.
+//
+// The errors whose messages are altered or whose Fields are rewritten are replaced by wrappers,
+// which keep the rest of the error tree, the stacktraces and the matching via errors.Is
+// of the original errors. If nothing needs rewriting, err itself is returned.
+func Error(err error, message func(string) string, fields func([]logging.Field) []logging.Field) (error, bool) {
+	return rewriteError(err, message, fields, 0)
+}
+
+func rewriteError(err error, message func(string) string,
+	fields func([]logging.Field) []logging.Field, depth int) (error, bool) {
+	if err == nil || depth >= maxErrorDepth {
+		return err, false
+	}
+	original := err.Error()
+	rewritten := rewrittenError{err: err, msg: message(original)}
+	replace := rewritten.msg != original
+	if ef, ok := err.(logging.ErrorFielder); ok {
+		rewritten.fields = ef.LogFields()
+		if fields != nil && len(rewritten.fields) > 0 {
+			rewritten.fields = fields(rewritten.fields)
+			replace = true
+		}
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause, replaced := rewriteError(u.Unwrap(), message, fields, depth+1); replace || replaced {
+			return &wrapError{rewrittenError: rewritten, cause: cause}, true
+		}
+	case interface{ Unwrap() []error }:
+		causes := append([]error(nil), u.Unwrap()...)
+		for i, cause := range causes {
+			var replaced bool
+			causes[i], replaced = rewriteError(cause, message, fields, depth+1)
+			replace = replace || replaced
+		}
+		if replace {
+			return &joinError{rewrittenError: rewritten, causes: causes}, true
+		}
+	default:
+		if replace {
+			return &rewritten, true
+		}
+	}
+	return err, false
+}
+
+// rewrittenError replaces an error whose message or carried Fields are rewritten.
+type rewrittenError struct {
+	err    error
+	msg    string
+	fields []logging.Field
+}
+
+func (e *rewrittenError) Error() string              { return e.msg }
+func (e *rewrittenError) LogFields() []logging.Field { return e.fields }
+func (e *rewrittenError) Callers() []uintptr         { return logging.ErrorCallers(e.err) }
+func (e *rewrittenError) Is(target error) bool       { return errors.Is(e.err, target) }
+
+// wrapError is a rewrittenError wrapping a single error.
+type wrapError struct {
+	rewrittenError
+	cause error
+}
+
+func (e *wrapError) Unwrap() error { return e.cause }
+
+// joinError is a rewrittenError wrapping multiple errors.
+type joinError struct {
+	rewrittenError
+	causes []error
+}
+
+func (e *joinError) Unwrap() []error { return e.causes }
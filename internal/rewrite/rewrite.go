@@ -0,0 +1,167 @@
+// Package rewrite provides the Logger wrapper shared by redact and sanitize,
+// which rewrites messages and Fields before passing them to the wrapped Logger.
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yimi-go/logging"
+)
+
+// Rewriter rewrites messages and Fields.
+type Rewriter struct {
+	// String rewrites messages.
+	String func(s string) string
+	// Fields rewrites the Fields of logs, which are resolved if lazy.
+	Fields func(fields []logging.Field) []logging.Field
+	// LazyFields rewrites the Fields added via WithField, keeping the lazy ones lazy.
+	LazyFields func(fields []logging.Field) []logging.Field
+}
+
+type rewritten struct {
+	logger   logging.Logger
+	rewriter *Rewriter
+}
+
+// Logger wraps logger into a new Logger, which rewrites messages and Fields via rewriter
+// before passing them to logger.
+//
+// Messages of the non-w methods are formatted by the wrapper first,
+// and Fields are rewritten only if the level is enabled.
+func Logger(logger logging.Logger, rewriter *Rewriter) logging.Logger {
+	// skip the wrapper methods to report the real callers.
+	return &rewritten{logger: logging.AddCallerSkip(logger, 1), rewriter: rewriter}
+}
+
+func (l *rewritten) message(v []any) string {
+	return l.rewriter.String(fmt.Sprint(v...))
+}
+
+func (l *rewritten) messageln(v []any) string {
+	return l.rewriter.String(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+func (l *rewritten) messagef(format string, v []any) string {
+	return l.rewriter.String(fmt.Sprintf(format, v...))
+}
+
+func (l *rewritten) Enabled(lvl logging.Level) bool {
+	return l.logger.Enabled(lvl)
+}
+func (l *rewritten) Debug(v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.logger.Debug(l.message(v))
+	}
+}
+func (l *rewritten) Debugln(v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.logger.Debug(l.messageln(v))
+	}
+}
+func (l *rewritten) Debugf(format string, v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.logger.Debug(l.messagef(format, v))
+	}
+}
+func (l *rewritten) Debugw(message string, field ...logging.Field) {
+	if l.Enabled(logging.DebugLevel) {
+		l.logger.Debugw(l.rewriter.String(message), l.rewriter.Fields(field)...)
+	}
+}
+func (l *rewritten) Info(v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.logger.Info(l.message(v))
+	}
+}
+func (l *rewritten) Infoln(v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.logger.Info(l.messageln(v))
+	}
+}
+func (l *rewritten) Infof(format string, v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.logger.Info(l.messagef(format, v))
+	}
+}
+func (l *rewritten) Infow(message string, field ...logging.Field) {
+	if l.Enabled(logging.InfoLevel) {
+		l.logger.Infow(l.rewriter.String(message), l.rewriter.Fields(field)...)
+	}
+}
+func (l *rewritten) Warn(v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.logger.Warn(l.message(v))
+	}
+}
+func (l *rewritten) Warnln(v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.logger.Warn(l.messageln(v))
+	}
+}
+func (l *rewritten) Warnf(format string, v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.logger.Warn(l.messagef(format, v))
+	}
+}
+func (l *rewritten) Warnw(message string, field ...logging.Field) {
+	if l.Enabled(logging.WarnLevel) {
+		l.logger.Warnw(l.rewriter.String(message), l.rewriter.Fields(field)...)
+	}
+}
+func (l *rewritten) Error(v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.logger.Error(l.message(v))
+	}
+}
+func (l *rewritten) Errorln(v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.logger.Error(l.messageln(v))
+	}
+}
+func (l *rewritten) Errorf(format string, v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.logger.Error(l.messagef(format, v))
+	}
+}
+func (l *rewritten) Errorw(message string, field ...logging.Field) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.logger.Errorw(l.rewriter.String(message), l.rewriter.Fields(field)...)
+	}
+}
+func (l *rewritten) WithField(field ...logging.Field) logging.Logger {
+	return &rewritten{
+		logger:   l.logger.WithField(l.rewriter.LazyFields(field)...),
+		rewriter: l.rewriter,
+	}
+}
+func (l *rewritten) WithGroup(name string) logging.Logger {
+	return &rewritten{
+		logger:   logging.WithGroup(l.logger, name),
+		rewriter: l.rewriter,
+	}
+}
+func (l *rewritten) WithCallerSkip(skip int) logging.Logger {
+	return &rewritten{
+		logger:   logging.AddCallerSkip(l.logger, skip),
+		rewriter: l.rewriter,
+	}
+}
+
+type rewrittenFactory struct {
+	factory  logging.Factory
+	rewriter *Rewriter
+}
+
+func (f *rewrittenFactory) Logger(name string) logging.Logger {
+	return Logger(f.factory.Logger(name), f.rewriter)
+}
+
+// Factory creates a new Factory that produces Loggers rewriting messages and Fields
+// via rewriter, whichever vendor the factory is.
+func Factory(factory logging.Factory, rewriter *Rewriter) logging.Factory {
+	return &rewrittenFactory{
+		factory:  factory,
+		rewriter: rewriter,
+	}
+}
@@ -1,6 +1,13 @@
 package logging
 
 // Logger is logger interface.
+//
+// The logging methods do nothing if their Levels are not enabled, and the non-w methods format
+// the messages only if enabled, so callers need not check Enabled before calling them.
+// Work done by callers before calling, e.g. computing arguments or formatting messages for
+// the w methods, should be guarded by Enabled if expensive. Fields that are expensive to compute
+// should be created via Lazy or LazyField instead, which are computed only when the log is
+// really going to be output.
 type Logger interface {
 	// LevelEnabler enforce a Logger provide a Enabled method.
 	LevelEnabler
@@ -9,22 +16,16 @@ type Logger interface {
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the Print method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Debug(v ...any)
 	// Debugln outputs a log at DebugLevel if the Logger enabled DebugLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Println method does but without an ending new line.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Debugln(v ...any)
 	// Debugf outputs a log at DebugLevel if the Logger enabled DebugLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Printf method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Debugf(format string, v ...any)
 	// Debugw outputs a log at DebugLevel if the Logger enabled DebugLevel.
 	//
@@ -35,29 +36,22 @@ type Logger interface {
 	// Read the references of the vendor you chose about that.
 	//
 	// If you want format you msg field, do it before you call this method.
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Debugw(message string, field ...Field)
 
 	// Info outputs a log at InfoLevel if the Logger enabled InfoLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the Print method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Info(v ...any)
 	// Infoln outputs a log at InfoLevel if the Logger enabled InfoLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Println method does but without an ending new line.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Infoln(v ...any)
 	// Infof outputs a log at InfoLevel if the Logger enabled InfoLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Printf method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Infof(format string, v ...any)
 	// Infow outputs a log at InfoLevel if the Logger enabled InfoLevel.
 	//
@@ -68,29 +62,22 @@ type Logger interface {
 	// Read the references of the vendor you chose about that.
 	//
 	// If you want format you msg field, do it before you call this method.
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Infow(message string, field ...Field)
 
 	// Warn outputs a log at WarnLevel if the Logger enabled WarnLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the Print method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Warn(v ...any)
 	// Warnln outputs a log at WarnLevel if the Logger enabled WarnLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Println method does but without an ending new line.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Warnln(v ...any)
 	// Warnf outputs a log at WarnLevel if the Logger enabled WarnLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Printf method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Warnf(format string, v ...any)
 	// Warnw outputs a log at WarnLevel if the Logger enabled WarnLevel.
 	//
@@ -101,29 +88,22 @@ type Logger interface {
 	// Read the references of the vendor you chose about that.
 	//
 	// If you want format you msg field, do it before you call this method.
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Warnw(message string, field ...Field)
 
 	// Error outputs a log at ErrorLevel if the Logger enabled ErrorLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the Print method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Error(v ...any)
 	// Errorln outputs a log at ErrorLevel if the Logger enabled ErrorLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Println method does but without an ending new line.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Errorln(v ...any)
 	// Errorf outputs a log at ErrorLevel if the Logger enabled ErrorLevel.
 	//
 	// The parameters would be used to forming the log msg field
 	// via formatting like the fmt.Printf method does.
-	//
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Errorf(format string, v ...any)
 	// Errorw outputs a log at ErrorLevel if the Logger enabled ErrorLevel.
 	//
@@ -134,7 +114,6 @@ type Logger interface {
 	// Read the references of the vendor you chose about that.
 	//
 	// If you want format you msg field, do it before you call this method.
-	// If formatting is expensive, you'd better call Enabled method before do that.
 	Errorw(message string, field ...Field)
 
 	// WithField returns a new Logger which wrap extra Fields, including fields of current Logger.
@@ -142,6 +121,7 @@ type Logger interface {
 	//
 	// For fields with same name, usually, the later one override the former and builtin ones.
 	// But a vendor may change this behavior by merge them.
-	// Read the references of the vendor you chose about that.
+	// Read the references of the vendor you chose about that,
+	// or wrap the Logger via MergeLogger to merge them by an explicit MergePolicy.
 	WithField(field ...Field) Logger
 }
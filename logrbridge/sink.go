@@ -0,0 +1,222 @@
+// Package logrbridge provides a logr.LogSink logging via a Factory, so that the libraries
+// logging through logr, e.g. Kubernetes client-go and controller-runtime, join the same output.
+package logrbridge
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/yimi-go/logging"
+)
+
+// NameSeparator joins the names added via WithName, the same as the logr convention.
+const NameSeparator = "/"
+
+// ErrorKey is the key of the errors passed to Error.
+const ErrorKey = "error"
+
+// Verbosity maps the logr V-levels to Levels.
+// V-levels not greater than Info are logged at InfoLevel, the ones not greater than Debug
+// at DebugLevel, and the others are dropped.
+type Verbosity struct {
+	Info  int
+	Debug int
+}
+
+// DefaultVerbosity logs V(0) at InfoLevel and all the others at DebugLevel.
+var DefaultVerbosity = Verbosity{Info: 0, Debug: math.MaxInt}
+
+type options struct {
+	names     map[string]Verbosity
+	verbosity Verbosity
+}
+
+// Option configures a Sink.
+type Option func(o *options)
+
+// WithVerbosity sets the Verbosity of the Loggers without one set via WithNameVerbosity.
+func WithVerbosity(v Verbosity) Option {
+	return func(o *options) {
+		o.verbosity = v
+	}
+}
+
+// WithNameVerbosity sets the Verbosity of the Logger named name and its descendants,
+// unless the descendants have their own ones. Names are joined by NameSeparator.
+func WithNameVerbosity(name string, v Verbosity) Option {
+	return func(o *options) {
+		o.names[name] = v
+	}
+}
+
+// verbosityOf returns the Verbosity set for name or its nearest ancestor.
+func (o *options) verbosityOf(name string) Verbosity {
+	for {
+		if v, ok := o.names[name]; ok {
+			return v
+		}
+		i := strings.LastIndex(name, NameSeparator)
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	if v, ok := o.names[""]; ok {
+		return v
+	}
+	return o.verbosity
+}
+
+// Sink is a logr.LogSink logging via the Loggers of a Factory.
+//
+// Names added via WithName are joined by NameSeparator into the names of the Loggers,
+// and values added via WithValues are added via WithField. Values are mapped to Fields
+// via logging.Infer, after logr.Marshaler values are marshaled, so logging.Field values
+// are renamed to their logr keys. Non-string keys are formatted, and the missing value
+// of a dangling key is nil.
+//
+// The callers of the logr methods are reported as callers, if the Loggers implement
+// logging.CallerSkipper.
+type Sink struct {
+	factory   logging.Factory
+	logger    logging.Logger
+	opts      *options
+	name      string
+	values    []logging.Field
+	depth     int
+	verbosity Verbosity
+}
+
+var _ logr.CallDepthLogSink = (*Sink)(nil)
+
+// NewSink creates a Sink logging via the Loggers of factory.
+func NewSink(factory logging.Factory, opts ...Option) *Sink {
+	o := &options{names: map[string]Verbosity{}, verbosity: DefaultVerbosity}
+	for _, opt := range opts {
+		opt(o)
+	}
+	s := &Sink{factory: factory, opts: o}
+	s.build()
+	return s
+}
+
+// NewLogger creates a logr.Logger logging via the Loggers of factory.
+func NewLogger(factory logging.Factory, opts ...Option) logr.Logger {
+	return logr.New(NewSink(factory, opts...))
+}
+
+// build creates the Logger from the name, values and depth of s.
+func (s *Sink) build() {
+	logger := s.factory.Logger(s.name)
+	if len(s.values) > 0 {
+		logger = logger.WithField(s.values...)
+	}
+	// skip the methods of Sink.
+	s.logger = logging.AddCallerSkip(logger, s.depth+1)
+	s.verbosity = s.opts.verbosityOf(s.name)
+}
+
+// level maps the V-level v to a Level, and reports whether it is logged.
+func (s *Sink) level(v int) (logging.Level, bool) {
+	switch {
+	case v <= s.verbosity.Info:
+		return logging.InfoLevel, true
+	case v <= s.verbosity.Debug:
+		return logging.DebugLevel, true
+	default:
+		return logging.OffLevel, false
+	}
+}
+
+// Init records the call depth of the logr.Logger.
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.depth = info.CallDepth
+	s.build()
+}
+
+// Enabled reports whether the V-level is logged and the Level mapped from it is enabled.
+func (s *Sink) Enabled(level int) bool {
+	lvl, ok := s.level(level)
+	return ok && s.logger.Enabled(lvl)
+}
+
+// Info logs msg at the Level mapped from the V-level.
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	lvl, ok := s.level(level)
+	if !ok || !s.logger.Enabled(lvl) {
+		return
+	}
+	fields := kvFields(nil, keysAndValues)
+	if lvl == logging.InfoLevel {
+		s.logger.Infow(msg, fields...)
+	} else {
+		s.logger.Debugw(msg, fields...)
+	}
+}
+
+// Error logs msg at ErrorLevel, with err keyed by ErrorKey unless it is nil.
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	if !s.logger.Enabled(logging.ErrorLevel) {
+		return
+	}
+	fields := make([]logging.Field, 0, 1+(len(keysAndValues)+1)/2)
+	if err != nil {
+		fields = append(fields, logging.NamedError(ErrorKey, err))
+	}
+	s.logger.Errorw(msg, kvFields(fields, keysAndValues)...)
+}
+
+// WithValues returns a Sink whose Logger has the Fields of keysAndValues added via WithField.
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	fields := kvFields(nil, keysAndValues)
+	if len(fields) == 0 {
+		return s
+	}
+	c := *s
+	c.values = make([]logging.Field, 0, len(s.values)+len(fields))
+	c.values = append(append(c.values, s.values...), fields...)
+	c.logger = s.logger.WithField(fields...)
+	return &c
+}
+
+// WithName returns a Sink logging via the Logger named by joining the names with NameSeparator.
+func (s *Sink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name == "" {
+		c.name = name
+	} else {
+		c.name += NameSeparator + name
+	}
+	c.build()
+	return &c
+}
+
+// WithCallDepth returns a Sink skipping extra depth frames when reporting callers.
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	c := *s
+	c.depth += depth
+	c.build()
+	return &c
+}
+
+// kvFields appends the Fields of the key-value pairs to fields.
+func kvFields(fields []logging.Field, keysAndValues []any) []logging.Field {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		var value any
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		if m, ok := value.(logr.Marshaler); ok {
+			value = m.MarshalLog()
+		}
+		fields = append(fields, logging.Infer(key, value))
+	}
+	return fields
+}
@@ -0,0 +1,126 @@
+package logrbridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+type marshaler struct{ id int }
+
+func (m marshaler) MarshalLog() any { return map[string]int{"id": m.id} }
+
+func TestSink(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	logger := NewLogger(rec)
+	logger.Info("info", "a", 1, "b", "x", "f", logging.Int("other", 2))
+	logger.V(3).Info("verbose", 2, marshaler{id: 7}, "dangling")
+	logger.Error(errors.New("boom"), "failed", "c", true)
+	logger.Error(nil, "no error")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 4)
+	assert.Equal(t, logging.InfoLevel, entries[0].Level)
+	assert.Equal(t, "info", entries[0].Message)
+	assert.Equal(t, []logging.Field{
+		logging.Int("a", 1),
+		logging.String("b", "x"),
+		logging.Int("f", 2),
+	}, entries[0].Fields)
+	assert.Equal(t, logging.DebugLevel, entries[1].Level)
+	assert.Equal(t, []logging.Field{
+		logging.Any("2", map[string]int{"id": 7}),
+		logging.Any("dangling", nil),
+	}, entries[1].Fields)
+	assert.Equal(t, logging.ErrorLevel, entries[2].Level)
+	assert.Equal(t, []logging.Field{
+		logging.NamedError(ErrorKey, errors.New("boom")),
+		logging.Bool("c", true),
+	}, entries[2].Fields)
+	assert.Empty(t, entries[3].Fields)
+	for _, e := range entries {
+		assert.Equal(t, "", e.Name)
+		assert.Equal(t, "github.com/yimi-go/logging/logrbridge.TestSink", e.Caller.Function)
+	}
+}
+
+func TestSink_withNameValues(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	base := NewLogger(rec).WithValues("app", "x")
+	child := base.WithName("controller").WithName("pod").WithValues("ns", "default")
+	child.Info("reconciled")
+	base.Info("started")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "controller/pod", entries[0].Name)
+	assert.Equal(t, []logging.Field{logging.String("app", "x"), logging.String("ns", "default")}, entries[0].Fields)
+	assert.Equal(t, "", entries[1].Name)
+	assert.Equal(t, []logging.Field{logging.String("app", "x")}, entries[1].Fields)
+	assert.Same(t, base.GetSink(), base.WithValues().GetSink())
+}
+
+func TestSink_verbosity(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	logger := NewLogger(rec,
+		WithVerbosity(Verbosity{Info: 1, Debug: 2}),
+		WithNameVerbosity("client-go", Verbosity{Info: -1, Debug: 4}),
+		WithNameVerbosity("client-go/cache", Verbosity{Info: 0, Debug: 0}),
+	)
+	assert.True(t, logger.V(1).Enabled())
+	assert.True(t, logger.V(2).Enabled())
+	assert.False(t, logger.V(3).Enabled())
+	logger.V(1).Info("v1")
+	logger.V(2).Info("v2")
+	logger.V(3).Info("v3")
+
+	client := logger.WithName("client-go")
+	client.Info("v0")
+	client.WithName("rest").V(4).Info("v4")
+	client.WithName("rest").V(5).Info("v5")
+	cache := client.WithName("cache")
+	cache.Info("cached")
+	assert.False(t, cache.V(1).Enabled())
+
+	var got []string
+	for _, e := range rec.Entries() {
+		got = append(got, e.Name+":"+e.Message+":"+e.Level.String())
+	}
+	assert.Equal(t, []string{
+		":v1:" + logging.InfoLevel.String(),
+		":v2:" + logging.DebugLevel.String(),
+		"client-go:v0:" + logging.DebugLevel.String(),
+		"client-go/rest:v4:" + logging.DebugLevel.String(),
+		"client-go/cache:cached:" + logging.InfoLevel.String(),
+	}, got)
+}
+
+func TestSink_levelDisabled(t *testing.T) {
+	rec := logtest.New(logging.ErrorLevel)
+	logger := NewLogger(rec, WithNameVerbosity("", Verbosity{Info: 0, Debug: 1}))
+	assert.False(t, logger.Enabled())
+	logger.Info("dropped")
+	logger.GetSink().Info(0, "dropped")
+	logger.Error(nil, "kept")
+	assert.Len(t, rec.Entries(), 1)
+
+	rec = logtest.New(logging.OffLevel)
+	NewLogger(rec).Error(errors.New("boom"), "dropped")
+	assert.Empty(t, rec.Entries())
+}
+
+func TestSink_callDepth(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	logger := NewLogger(rec)
+	helper := func(l logr.Logger) {
+		l.WithCallDepth(1).Info("helped")
+	}
+	helper(logger.WithName("a"))
+	assert.Equal(t, "github.com/yimi-go/logging/logrbridge.TestSink_callDepth",
+		rec.Entries()[0].Caller.Function)
+}
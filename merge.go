@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+type mergeLogger struct {
+	logger Logger
+	set    FieldSet
+}
+
+// MergeLogger wraps logger into a new Logger, which merges Fields with duplicate keys by policy
+// before passing them to logger, so that the behavior does not change with vendors.
+//
+// Fields added via WithField are held by the wrapper, and passed to logger with the Fields
+// of each log, since merged Fields could not be removed from the vendor Logger.
+// WithGroup passes the held Fields to logger before nesting, as the keys of different Groups never conflict.
+func MergeLogger(logger Logger, policy MergePolicy) Logger {
+	// skip the wrapper methods to report the real callers.
+	return &mergeLogger{logger: AddCallerSkip(logger, 1), set: FieldSet{policy: policy}}
+}
+
+func (l *mergeLogger) fields(field []Field) []Field {
+	if len(field) == 0 {
+		return l.set.fields
+	}
+	return l.set.With(field...).fields
+}
+
+func (l *mergeLogger) Enabled(lvl Level) bool {
+	return l.logger.Enabled(lvl)
+}
+func (l *mergeLogger) Debug(v ...any) {
+	if l.Enabled(DebugLevel) {
+		l.logger.Debugw(fmt.Sprint(v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Debugln(v ...any) {
+	if l.Enabled(DebugLevel) {
+		l.logger.Debugw(strings.TrimSuffix(fmt.Sprintln(v...), "\n"), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Debugf(format string, v ...any) {
+	if l.Enabled(DebugLevel) {
+		l.logger.Debugw(fmt.Sprintf(format, v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Debugw(message string, field ...Field) {
+	if l.Enabled(DebugLevel) {
+		l.logger.Debugw(message, l.fields(field)...)
+	}
+}
+func (l *mergeLogger) Info(v ...any) {
+	if l.Enabled(InfoLevel) {
+		l.logger.Infow(fmt.Sprint(v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Infoln(v ...any) {
+	if l.Enabled(InfoLevel) {
+		l.logger.Infow(strings.TrimSuffix(fmt.Sprintln(v...), "\n"), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Infof(format string, v ...any) {
+	if l.Enabled(InfoLevel) {
+		l.logger.Infow(fmt.Sprintf(format, v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Infow(message string, field ...Field) {
+	if l.Enabled(InfoLevel) {
+		l.logger.Infow(message, l.fields(field)...)
+	}
+}
+func (l *mergeLogger) Warn(v ...any) {
+	if l.Enabled(WarnLevel) {
+		l.logger.Warnw(fmt.Sprint(v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Warnln(v ...any) {
+	if l.Enabled(WarnLevel) {
+		l.logger.Warnw(strings.TrimSuffix(fmt.Sprintln(v...), "\n"), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Warnf(format string, v ...any) {
+	if l.Enabled(WarnLevel) {
+		l.logger.Warnw(fmt.Sprintf(format, v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Warnw(message string, field ...Field) {
+	if l.Enabled(WarnLevel) {
+		l.logger.Warnw(message, l.fields(field)...)
+	}
+}
+func (l *mergeLogger) Error(v ...any) {
+	if l.Enabled(ErrorLevel) {
+		l.logger.Errorw(fmt.Sprint(v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Errorln(v ...any) {
+	if l.Enabled(ErrorLevel) {
+		l.logger.Errorw(strings.TrimSuffix(fmt.Sprintln(v...), "\n"), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Errorf(format string, v ...any) {
+	if l.Enabled(ErrorLevel) {
+		l.logger.Errorw(fmt.Sprintf(format, v...), l.set.fields...)
+	}
+}
+func (l *mergeLogger) Errorw(message string, field ...Field) {
+	if l.Enabled(ErrorLevel) {
+		l.logger.Errorw(message, l.fields(field)...)
+	}
+}
+func (l *mergeLogger) WithField(field ...Field) Logger {
+	return &mergeLogger{logger: l.logger, set: l.set.With(field...)}
+}
+func (l *mergeLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+	logger := l.logger
+	if l.set.Len() > 0 {
+		logger = logger.WithField(l.set.fields...)
+	}
+	return &mergeLogger{logger: WithGroup(logger, name), set: FieldSet{policy: l.set.policy}}
+}
+func (l *mergeLogger) WithCallerSkip(skip int) Logger {
+	return &mergeLogger{logger: AddCallerSkip(l.logger, skip), set: l.set}
+}
+
+type mergeFactory struct {
+	factory Factory
+	policy  MergePolicy
+}
+
+func (f *mergeFactory) Logger(name string) Logger {
+	return MergeLogger(f.factory.Logger(name), f.policy)
+}
+
+// MergeFactory creates a new Factory that produces Loggers merging Fields with duplicate keys
+// by policy, whichever vendor the factory is.
+func MergeFactory(factory Factory, policy MergePolicy) Factory {
+	return &mergeFactory{factory: factory, policy: policy}
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeLogger(t *testing.T) {
+	var entries []tEntry
+	factory := MergeFactory(tFactoryFunc(func(string) Logger { return &tel{entries: &entries, lvl: InfoLevel} }), LastWins)
+	logger := factory.Logger("test").WithField(Int("a", 1), Int("b", 1))
+
+	logger.Debugw("debug", Int("a", 2))
+	logger.Info("info ", 1)
+	logger.Infoln("info", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Errorw("error", Int("a", 2), Int("c", 1))
+	logger.WithField(Int("b", 2)).Error("error")
+	WithGroup(logger, "g").WithField(Int("a", 3)).Infow("group", Int("a", 4))
+	WithGroup(logger, "").Warn("no group")
+
+	assert.Len(t, entries, 7)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging.TestMergeLogger", e.caller.Function)
+	}
+	base := []Field{Int("a", 1), Int("b", 1)}
+	assert.Equal(t, tEntry{caller: entries[0].caller, msg: "info 1", fields: base, lvl: InfoLevel}, entries[0])
+	assert.Equal(t, "info 2", entries[1].msg)
+	assert.Equal(t, tEntry{caller: entries[2].caller, msg: "warn 3", fields: base, lvl: WarnLevel}, entries[2])
+	assert.Equal(t, []Field{Int("a", 2), Int("b", 1), Int("c", 1)}, entries[3].fields)
+	assert.Equal(t, []Field{Int("a", 1), Int("b", 2)}, entries[4].fields)
+	assert.Equal(t, []Field{Int("a", 1), Int("b", 1), Namespace("g"), Int("a", 4)}, entries[5].fields)
+	assert.Equal(t, base, entries[6].fields)
+}
+
+func TestMergeLogger_keepAll(t *testing.T) {
+	var entries []tEntry
+	logger := MergeLogger(&tel{entries: &entries, lvl: DebugLevel}, KeepAll).WithField(Int("a", 1))
+	logger.Debugw("debug", Int("a", 2))
+	logger.Debugln("debug")
+	logger.Debugf("debug")
+	logger.Debug("debug")
+	assert.Len(t, entries, 4)
+	assert.Equal(t, []Field{Int("a", 1), Int("a", 2)}, entries[0].fields)
+}
+
+func TestMergeLogger_contextFields(t *testing.T) {
+	var entries []tEntry
+	ctx := NewContext(context.Background(), Int("a", 2), Int("c", 1))
+	logger := MergeLogger(&tel{entries: &entries, lvl: InfoLevel}, LastWins).WithField(Int("a", 1), Int("b", 1))
+	WithContextField(ctx, logger).Infow("merged")
+
+	plain := (&tel{entries: &entries, lvl: InfoLevel}).WithField(Int("a", 1))
+	WithContextField(ctx, plain).Infow("kept")
+
+	assert.Equal(t, []Field{Int("a", 2), Int("b", 1), Int("c", 1)}, entries[0].fields)
+	assert.Equal(t, []Field{Int("a", 1), Int("a", 2), Int("c", 1)}, entries[1].fields)
+}
+
+type tFactoryFunc func(name string) Logger
+
+func (f tFactoryFunc) Logger(name string) Logger { return f(name) }
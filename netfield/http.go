@@ -0,0 +1,171 @@
+package netfield
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/semconv"
+)
+
+type routeKey struct{}
+
+// ContextWithRoute returns a context carrying the matched route template of the request,
+// e.g. "/users/{id}", for HTTPRequest to log. Routers or middlewares should call it.
+func ContextWithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// Route returns the route template set via ContextWithRoute, or "" if not set.
+func Route(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey{}).(string)
+	return route
+}
+
+// HTTPRequest builds Fields of r following the Schema set via semconv.SwapSchema,
+// see HTTPRequestIn.
+func HTTPRequest(r *http.Request) []logging.Field {
+	return HTTPRequestIn(semconv.GetSchema(), r)
+}
+
+// HTTPResponse builds Fields of a response following the Schema set via semconv.SwapSchema,
+// see HTTPResponseIn.
+func HTTPResponse(status int, size int64, latency time.Duration) []logging.Field {
+	return HTTPResponseIn(semconv.GetSchema(), status, size, latency)
+}
+
+// httpRequestKeys are the semconv Keys of the Fields built by HTTPRequestIn.
+var httpRequestKeys = []semconv.Key{
+	semconv.HTTPRequestMethod,
+	semconv.HTTPRoute,
+	semconv.URLFull,
+	semconv.URLPath,
+	semconv.UserAgent,
+	semconv.ClientAddress,
+	semconv.ClientPort,
+	semconv.NetworkProtocolName,
+	semconv.HTTPVersion,
+}
+
+// HTTPRequestKeys returns the keys of the top-level Groups HTTPRequestIn may build in schema,
+// e.g. "http" and "url", for removing the Fields of an inbound request from a context
+// via logging.NewContextWithout. Note that HTTPResponseIn builds an "http" Group as well.
+func HTTPRequestKeys(schema semconv.Schema) []string {
+	keys := make([]string, 0, len(httpRequestKeys))
+	for _, key := range httpRequestKeys {
+		name := key.Name(schema)
+		if name == "" {
+			continue
+		}
+		root, _, _ := strings.Cut(name, ".")
+		if !slices.Contains(keys, root) {
+			keys = append(keys, root)
+		}
+	}
+	return keys
+}
+
+// HTTPRequestIn builds the method, route, URL, user agent, remote address and protocol of r
+// into Fields of GroupType nested by the names of the semconv Keys in schema via semconv.Nest,
+// e.g. Group("http", Group("request", String("method", "GET"))) for "http.request.method",
+// and Group("url", String("full", ...)) for "url.full".
+//
+// Empty values are omitted, and the credentials of the URL are stripped.
+// A nil r results in no Fields.
+func HTTPRequestIn(schema semconv.Schema, r *http.Request) []logging.Field {
+	if r == nil {
+		return nil
+	}
+	fields := make([]logging.Field, 0, 9)
+	add := func(key semconv.Key, value string) {
+		if name := key.Name(schema); name != "" && value != "" {
+			fields = append(fields, logging.String(name, value))
+		}
+	}
+	add(semconv.HTTPRequestMethod, r.Method)
+	add(semconv.HTTPRoute, Route(r.Context()))
+	add(semconv.URLFull, requestURL(r))
+	if r.URL != nil {
+		add(semconv.URLPath, r.URL.Path)
+	}
+	add(semconv.UserAgent, r.UserAgent())
+	host, port := splitHostPort(r.RemoteAddr)
+	add(semconv.ClientAddress, host)
+	if port > 0 {
+		fields = append(fields, logging.Int(semconv.ClientPort.Name(schema), port))
+	}
+	if schema == semconv.OTel {
+		add(semconv.NetworkProtocolName, "http")
+	}
+	add(semconv.HTTPVersion, protoVersion(r))
+	return semconv.Nest(fields...)
+}
+
+// HTTPResponseIn builds the status, body size and latency of a response into Fields of GroupType
+// nested by the names of the semconv Keys in schema, e.g. Group("http", Group("response",
+// Int("status_code", 200))) for "http.response.status_code", see HTTPRequestIn.
+// The "http" Group could be merged with the one of HTTPRequestIn via semconv.Nest.
+//
+// A non-positive status and a negative size are omitted as unknown.
+// The latency is in nanoseconds for ECS "event.duration", and in seconds for
+// OTel "http.server.request.duration".
+func HTTPResponseIn(schema semconv.Schema, status int, size int64, latency time.Duration) []logging.Field {
+	fields := make([]logging.Field, 0, 3)
+	if status > 0 {
+		fields = append(fields, logging.Int(semconv.HTTPResponseStatusCode.Name(schema), status))
+	}
+	if size >= 0 {
+		fields = append(fields, logging.Int64(semconv.HTTPResponseBodySize.Name(schema), size))
+	}
+	duration := semconv.HTTPServerDuration.Name(schema)
+	if semconv.HTTPServerDuration.Kind(schema) == semconv.IntKind {
+		fields = append(fields, logging.Int64(duration, latency.Nanoseconds()))
+	} else {
+		fields = append(fields, logging.Float64(duration, latency.Seconds()))
+	}
+	return semconv.Nest(fields...)
+}
+
+// requestURL returns the absolute URL of r without credentials.
+// The URLs of server requests, which only have the paths, are completed by Host and TLS.
+func requestURL(r *http.Request) string {
+	if r.URL == nil {
+		return ""
+	}
+	u := StripCredentials(r.URL)
+	if u.IsAbs() || r.Host == "" {
+		return u.String()
+	}
+	abs := *u
+	abs.Host = r.Host
+	abs.Scheme = "http"
+	if r.TLS != nil {
+		abs.Scheme = "https"
+	}
+	return (&abs).String()
+}
+
+func splitHostPort(addr string) (string, int) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 0
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+// protoVersion returns the HTTP version of r, e.g. "1.1" and "2".
+func protoVersion(r *http.Request) string {
+	if r.ProtoMajor == 0 {
+		return ""
+	}
+	if r.ProtoMajor >= 2 && r.ProtoMinor == 0 {
+		return strconv.Itoa(r.ProtoMajor)
+	}
+	return strconv.Itoa(r.ProtoMajor) + "." + strconv.Itoa(r.ProtoMinor)
+}
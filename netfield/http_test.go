@@ -0,0 +1,141 @@
+package netfield
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/semconv"
+)
+
+func newRequest() *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/users/1?q=1", nil)
+	r.Header.Set("User-Agent", "ua")
+	r.RemoteAddr = "10.0.0.1:1234"
+	return r.WithContext(ContextWithRoute(r.Context(), "/users/{id}"))
+}
+
+func TestHTTPRequest(t *testing.T) {
+	r := newRequest()
+	assert.Equal(t, []logging.Field{
+		logging.Group("http",
+			logging.Group("request", logging.String("method", "GET")),
+			logging.String("route", "/users/{id}"),
+		),
+		logging.Group("url",
+			logging.String("full", "http://example.com/users/1?q=1"),
+			logging.String("path", "/users/1"),
+		),
+		logging.Group("user_agent", logging.String("original", "ua")),
+		logging.Group("client",
+			logging.String("address", "10.0.0.1"),
+			logging.Int("port", 1234),
+		),
+		logging.Group("network", logging.Group("protocol",
+			logging.String("name", "http"),
+			logging.String("version", "1.1"),
+		)),
+	}, HTTPRequest(r))
+
+	defer semconv.SwapSchema(semconv.SwapSchema(semconv.ECS))
+	assert.Equal(t, []logging.Field{
+		logging.Group("http",
+			logging.Group("request", logging.String("method", "GET")),
+			logging.String("route", "/users/{id}"),
+			logging.String("version", "1.1"),
+		),
+		logging.Group("url",
+			logging.String("original", "http://example.com/users/1?q=1"),
+			logging.String("path", "/users/1"),
+		),
+		logging.Group("user_agent", logging.String("original", "ua")),
+		logging.Group("source",
+			logging.String("address", "10.0.0.1"),
+			logging.Int("port", 1234),
+		),
+	}, HTTPRequest(r))
+}
+
+func TestHTTPRequest_client(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "https://u:p@example.com/a", nil)
+	r.ProtoMajor, r.ProtoMinor = 2, 0
+	assert.Equal(t, []logging.Field{
+		logging.Group("http", logging.Group("request", logging.String("method", "POST"))),
+		logging.Group("url",
+			logging.String("full", "https://example.com/a"),
+			logging.String("path", "/a"),
+		),
+		logging.Group("network", logging.Group("protocol",
+			logging.String("name", "http"),
+			logging.String("version", "2"),
+		)),
+	}, HTTPRequestIn(semconv.OTel, r))
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.TLS = &tls.ConnectionState{}
+	r.RemoteAddr = "pipe"
+	assert.Equal(t, []logging.Field{
+		logging.Group("http",
+			logging.Group("request", logging.String("method", "GET")),
+			logging.String("version", "1.1"),
+		),
+		logging.Group("url",
+			logging.String("original", "https://example.com/"),
+			logging.String("path", "/"),
+		),
+		logging.Group("source", logging.String("address", "pipe")),
+	}, HTTPRequestIn(semconv.ECS, r))
+
+	assert.Nil(t, HTTPRequestIn(semconv.OTel, nil))
+	assert.Equal(t, "", Route(r.Context()))
+}
+
+func TestHTTPResponse(t *testing.T) {
+	assert.Equal(t, []logging.Field{
+		logging.Group("http",
+			logging.Group("response",
+				logging.Int("status_code", 200),
+				logging.Group("body", logging.Int64("size", 10)),
+			),
+			logging.Group("server", logging.Group("request", logging.Float64("duration", 1.5))),
+		),
+	}, HTTPResponse(200, 10, 1500*time.Millisecond))
+	assert.Equal(t, []logging.Field{
+		logging.Group("http", logging.Group("response",
+			logging.Int("status_code", 404),
+			logging.Group("body", logging.Int64("bytes", 0)),
+		)),
+		logging.Group("event", logging.Int64("duration", int64(time.Millisecond))),
+	}, HTTPResponseIn(semconv.ECS, 404, 0, time.Millisecond))
+	assert.Equal(t, []logging.Field{
+		logging.Group("event", logging.Int64("duration", 0)),
+	}, HTTPResponseIn(semconv.ECS, 0, -1, 0))
+}
+
+func TestHTTP_semconv(t *testing.T) {
+	for _, schema := range []semconv.Schema{semconv.OTel, semconv.ECS} {
+		var violations []semconv.Violation
+		v := semconv.NewValidator(schema, semconv.Warn)
+		fields := semconv.Nest(append(HTTPRequestIn(schema, newRequest()),
+			HTTPResponseIn(schema, 200, 10, time.Second)...)...)
+		assert.Len(t, v.Fields(fields, func(v semconv.Violation) { violations = append(violations, v) }),
+			len(fields))
+		assert.Empty(t, violations)
+	}
+}
+
+func TestHTTPRequestKeys(t *testing.T) {
+	for _, schema := range []semconv.Schema{semconv.OTel, semconv.ECS} {
+		keys := HTTPRequestKeys(schema)
+		for _, f := range HTTPRequestIn(schema, newRequest()) {
+			assert.Contains(t, keys, f.Key())
+		}
+	}
+	assert.Equal(t, []string{"http", "url", "user_agent", "client", "network"}, HTTPRequestKeys(semconv.OTel))
+	assert.Equal(t, []string{"http", "url", "user_agent", "source", "network"}, HTTPRequestKeys(semconv.ECS))
+}
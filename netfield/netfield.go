@@ -0,0 +1,170 @@
+// Package netfield provides Field constructors for network and web values,
+// so that services render them consistently.
+package netfield
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yimi-go/logging"
+)
+
+// defaultSensitiveHeaders are the canonical names of headers masked by Header.
+var defaultSensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"X-Csrf-Token",
+}
+
+// IP creates a Field of StringType with the textual form of ip.
+// A nil or empty ip results a Field of UnknownType with nil value.
+func IP(key string, ip net.IP) logging.Field {
+	if len(ip) == 0 {
+		return logging.Any(key, nil)
+	}
+	return logging.String(key, ip.String())
+}
+
+// Addr creates a Field of StringType with the textual form of addr.
+// An invalid addr results a Field of UnknownType with nil value.
+func Addr(key string, addr netip.Addr) logging.Field {
+	if !addr.IsValid() {
+		return logging.Any(key, nil)
+	}
+	return logging.String(key, addr.String())
+}
+
+// AddrPort creates a Field of StringType with the textual form of addr, e.g. "[::1]:80".
+// An invalid addr results a Field of UnknownType with nil value.
+func AddrPort(key string, addr netip.AddrPort) logging.Field {
+	if !addr.IsValid() {
+		return logging.Any(key, nil)
+	}
+	return logging.String(key, addr.String())
+}
+
+// Prefix creates a Field of StringType with the CIDR notation of prefix.
+// An invalid prefix results a Field of UnknownType with nil value.
+func Prefix(key string, prefix netip.Prefix) logging.Field {
+	if !prefix.IsValid() {
+		return logging.Any(key, nil)
+	}
+	return logging.String(key, prefix.String())
+}
+
+// URL creates a Field of StringType with the textual form of u, with the user info stripped.
+// A nil u results a Field of UnknownType with nil value.
+func URL(key string, u *url.URL) logging.Field {
+	if u == nil {
+		return logging.Any(key, nil)
+	}
+	return logging.String(key, StripCredentials(u).String())
+}
+
+// StripCredentials returns u without the user info. u is not modified.
+func StripCredentials(u *url.URL) *url.URL {
+	if u == nil || u.User == nil {
+		return u
+	}
+	stripped := *u
+	stripped.User = nil
+	return &stripped
+}
+
+// Header creates a Field of GroupType with a Field of StringType for each header,
+// sorted by the canonical header names. Multiple values of a header are joined with ", ".
+//
+// The values of sensitive headers, e.g. Authorization and Cookie, and of the extra
+// sensitive headers given, are replaced with logging.RedactedValue.
+func Header(key string, h http.Header, sensitive ...string) logging.Field {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]logging.Field, 0, len(names))
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if isSensitiveHeader(canonical, sensitive) {
+			fields = append(fields, logging.String(canonical, logging.RedactedValue))
+			continue
+		}
+		fields = append(fields, logging.String(canonical, strings.Join(h[name], ", ")))
+	}
+	return logging.Group(key, fields...)
+}
+
+func isSensitiveHeader(canonical string, extra []string) bool {
+	for _, name := range defaultSensitiveHeaders {
+		if canonical == name {
+			return true
+		}
+	}
+	for _, name := range extra {
+		if canonical == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ByteSize is a size in bytes, which is rendered in IEC units, e.g. "1.5 KiB".
+type ByteSize int64
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// String returns the size in the largest IEC unit not greater than it,
+// with at most one decimal place.
+func (s ByteSize) String() string {
+	neg := s < 0
+	abs := uint64(s)
+	if neg {
+		abs = -abs
+	}
+	var b []byte
+	if neg {
+		b = append(b, '-')
+	}
+	if abs < 1024 {
+		b = strconv.AppendUint(b, abs, 10)
+		return string(append(b, " B"...))
+	}
+	v := float64(abs)
+	unit := 0
+	for v >= 1024 && unit < len(byteUnits)-1 {
+		v /= 1024
+		unit++
+	}
+	num := strconv.FormatFloat(v, 'f', 1, 64)
+	num = strings.TrimSuffix(num, ".0")
+	b = append(b, num...)
+	b = append(b, ' ')
+	return string(append(b, byteUnits[unit]...))
+}
+
+// Bytes creates a Field of StringerType with ByteSize value,
+// so that vendors could render it human-readably or take the number via Interface.
+func Bytes(key string, size int64) logging.Field {
+	return logging.Stringer(key, ByteSize(size))
+}
+
+// RegisterEncoders registers encoders via logging.RegisterEncoder, so that values of
+// net.IP, netip.Addr, netip.AddrPort, netip.Prefix, *url.URL and http.Header arriving
+// via logging.Any are rendered as the constructors of this package do.
+func RegisterEncoders() {
+	logging.RegisterEncoder(IP)
+	logging.RegisterEncoder(Addr)
+	logging.RegisterEncoder(AddrPort)
+	logging.RegisterEncoder(Prefix)
+	logging.RegisterEncoder(URL)
+	logging.RegisterEncoder(func(key string, h http.Header) logging.Field { return Header(key, h) })
+}
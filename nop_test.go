@@ -6,7 +6,7 @@ import (
 )
 
 func sf(key, value string) Field {
-	return field{value, key, StringType}
+	return String(key, value)
 }
 
 func TestNopLogger(t *testing.T) {
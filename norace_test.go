@@ -0,0 +1,7 @@
+//go:build !race
+
+package logging
+
+// raceEnabled reports whether the race detector is enabled,
+// which makes sync.Pool drop items randomly.
+const raceEnabled = false
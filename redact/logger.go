@@ -0,0 +1,26 @@
+package redact
+
+import (
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/rewrite"
+)
+
+// rewriter returns the Rewriter redacting via r.
+func (r *Redactor) rewriter() *rewrite.Rewriter {
+	return &rewrite.Rewriter{String: r.String, Fields: r.Fields, LazyFields: r.lazyFields}
+}
+
+// Logger wraps logger into a new Logger, which redacts messages and Fields via redactor
+// before passing them to logger.
+//
+// Messages of the non-w methods are formatted by the wrapper first,
+// and Fields are resolved and redacted only if the level is enabled.
+func Logger(logger logging.Logger, redactor *Redactor) logging.Logger {
+	return rewrite.Logger(logger, redactor.rewriter())
+}
+
+// Redacted creates a new Factory that produces Loggers redacting messages and Fields
+// via redactor, whichever vendor the factory is.
+func Redacted(factory logging.Factory, redactor *Redactor) logging.Factory {
+	return rewrite.Factory(factory, redactor.rewriter())
+}
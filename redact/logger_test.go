@@ -0,0 +1,62 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+func TestRedacted(t *testing.T) {
+	rec := logtest.New(logging.InfoLevel)
+	r := New(Keys(Mask, "password"), Values(Mask, Email))
+	logger := Redacted(rec, r).Logger("test")
+
+	logger.Debug("foo@example.com")
+	logger.Info("to ", "foo@example.com")
+	logger.Infoln("to", "foo@example.com")
+	logger.Warnf("to %s", "foo@example.com")
+	logger.Errorw("to foo@example.com", logging.String("password", "p"))
+	logging.WithGroup(logger, "req").
+		WithField(logging.String("password", "p")).
+		Infow("login", logging.String("email", "foo@example.com"))
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 5)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/redact.TestRedacted", e.Caller.Function)
+	}
+	for _, e := range entries[:4] {
+		assert.Equal(t, "test", e.Name)
+		assert.Equal(t, "to [REDACTED]", e.Message)
+	}
+	assert.Equal(t, logging.InfoLevel, entries[0].Level)
+	assert.Equal(t, logging.WarnLevel, entries[2].Level)
+	assert.Equal(t, logging.ErrorLevel, entries[3].Level)
+	assert.Equal(t, []logging.Field{logging.String("password", logging.RedactedValue)}, entries[3].Fields)
+	assert.Equal(t, []logging.Field{
+		logging.Namespace("req"),
+		logging.String("password", logging.RedactedValue),
+		logging.String("email", logging.RedactedValue),
+	}, entries[4].Fields)
+}
+
+func TestRedacted_disabled(t *testing.T) {
+	rec := logtest.New(logging.ErrorLevel)
+	logger := Logger(rec, New(Values(Mask, Email)))
+	calls := 0
+	lazy := logging.Lazy("email", func() any {
+		calls++
+		return "foo@example.com"
+	})
+	logger.Debugw("debug", lazy)
+	logger.Infow("info", lazy)
+	logger.Warnw("warn", lazy)
+	logger.Debugln("debug")
+	logger.Infof("info")
+	logger.Warn("warn")
+	assert.Empty(t, rec.Entries())
+	assert.Equal(t, 0, calls)
+}
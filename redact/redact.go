@@ -0,0 +1,336 @@
+// Package redact provides a logger wrapper that masks, hashes or drops sensitive values
+// before they reach the vendor.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/rewrite"
+)
+
+// Action is the way a sensitive value is redacted.
+type Action uint8
+
+const (
+	// Mask replaces the sensitive value with the mask, logging.RedactedValue by default.
+	Mask Action = iota
+	// Hash replaces the sensitive value with its (salted) SHA-256 digest,
+	// so that equal values could still be correlated.
+	Hash
+	// Drop removes the whole Field carrying the sensitive value.
+	// Sensitive substrings of log messages are removed.
+	Drop
+)
+
+// Detector detects sensitive substrings of values.
+type Detector struct {
+	// Pattern matches the sensitive substrings.
+	// If Pattern has capturing groups, only the first group is redacted.
+	Pattern *regexp.Regexp
+	// Validate is an optional function to reduce false positives of Pattern matches.
+	Validate func(match string) bool
+	// Name is the detector name.
+	Name string
+}
+
+var (
+	// Email detects email addresses.
+	Email = &Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	// CreditCard detects credit card numbers, which may be separated by spaces or dashes.
+	CreditCard = &Detector{
+		Name:     "credit_card",
+		Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: luhn,
+	}
+	// BearerToken detects tokens of bearer authorization.
+	BearerToken = &Detector{
+		Name:    "bearer_token",
+		Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	}
+	// JWT detects JSON web tokens.
+	JWT = &Detector{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+)
+
+func luhn(match string) bool {
+	sum, n := 0, 0
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+type keyRule struct {
+	keys    map[string]struct{}
+	pattern *regexp.Regexp
+	action  Action
+}
+
+func (r *keyRule) match(key string) bool {
+	if r.pattern != nil {
+		return r.pattern.MatchString(key)
+	}
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+type valueRule struct {
+	detectors []*Detector
+	action    Action
+}
+
+// Option configures a Redactor.
+type Option func(r *Redactor)
+
+// Keys redacts values of Fields whose keys equal any of keys, case-insensitively.
+func Keys(action Action, keys ...string) Option {
+	return func(r *Redactor) {
+		rule := keyRule{keys: make(map[string]struct{}, len(keys)), action: action}
+		for _, key := range keys {
+			rule.keys[strings.ToLower(key)] = struct{}{}
+		}
+		r.keyRules = append(r.keyRules, rule)
+	}
+}
+
+// KeyPattern redacts values of Fields whose keys match pattern.
+func KeyPattern(action Action, pattern *regexp.Regexp) Option {
+	return func(r *Redactor) {
+		r.keyRules = append(r.keyRules, keyRule{pattern: pattern, action: action})
+	}
+}
+
+// Values redacts substrings detected by detectors, of string Field values and log messages.
+func Values(action Action, detectors ...*Detector) Option {
+	return func(r *Redactor) {
+		r.valueRules = append(r.valueRules, valueRule{detectors: detectors, action: action})
+	}
+}
+
+// MaskWith sets the mask replacing the values redacted by Mask.
+func MaskWith(mask string) Option {
+	return func(r *Redactor) {
+		r.mask = mask
+	}
+}
+
+// Salt sets the key of HMAC-SHA256 used by Hash, which prevents hashed values being
+// recovered by a dictionary attack.
+func Salt(salt []byte) Option {
+	return func(r *Redactor) {
+		r.salt = salt
+	}
+}
+
+// Redactor redacts sensitive values of Fields and messages.
+// Rules are applied in the order of the Options.
+// A Redactor is immutable and safe for concurrent use.
+type Redactor struct {
+	mask       string
+	salt       []byte
+	keyRules   []keyRule
+	valueRules []valueRule
+}
+
+// New creates a Redactor with the given Options.
+func New(opts ...Option) *Redactor {
+	r := &Redactor{mask: logging.RedactedValue}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Redactor) hash(s string) string {
+	var sum []byte
+	if len(r.salt) > 0 {
+		mac := hmac.New(sha256.New, r.salt)
+		mac.Write([]byte(s))
+		sum = mac.Sum(nil)
+	} else {
+		digest := sha256.Sum256([]byte(s))
+		sum = digest[:]
+	}
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// String redacts the sensitive substrings of s detected by value rules.
+// Substrings redacted by Drop are removed.
+func (r *Redactor) String(s string) string {
+	s, _, _ = r.redactString(s)
+	return s
+}
+
+// redactString returns the redacted string, whether s is altered and whether s should be dropped.
+func (r *Redactor) redactString(s string) (string, bool, bool) {
+	altered, dropped := false, false
+	for _, rule := range r.valueRules {
+		for _, d := range rule.detectors {
+			var drop bool
+			s, drop = r.replace(s, d, rule.action, &altered)
+			dropped = dropped || drop
+		}
+	}
+	return s, altered, dropped
+}
+
+func (r *Redactor) replace(s string, d *Detector, action Action, altered *bool) (string, bool) {
+	matches := d.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, false
+	}
+	var b strings.Builder
+	last, drop := 0, false
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) > 2 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if d.Validate != nil && !d.Validate(s[start:end]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		switch action {
+		case Mask:
+			b.WriteString(r.mask)
+		case Hash:
+			b.WriteString(r.hash(s[start:end]))
+		case Drop:
+			drop = true
+		}
+		last = end
+		*altered = true
+	}
+	b.WriteString(s[last:])
+	return b.String(), drop
+}
+
+// Field redacts f according to the rules, and reports whether f should be kept.
+//
+// Key rules are checked first, and the first matching one takes effect.
+// Value rules are applied to Fields carrying strings, fmt.Stringers and errors,
+// and to the children of Groups. Errors are redacted along with the errors they wrap
+// and the Fields they carry, see logging.ErrorFields, and the altered ones are replaced by wrappers
+// keeping the error trees and stacktraces.
+// Fields of LazyType are resolved before being redacted.
+func (r *Redactor) Field(f logging.Field) (logging.Field, bool) {
+	f = logging.Resolve(f)
+	for i := range r.keyRules {
+		rule := &r.keyRules[i]
+		if !rule.match(f.Key()) {
+			continue
+		}
+		switch rule.action {
+		case Hash:
+			if s, ok := stringValue(f); ok {
+				return logging.String(f.Key(), r.hash(s)), true
+			}
+			return logging.String(f.Key(), r.hash(fmt.Sprint(f.Value()))), true
+		case Drop:
+			return f, false
+		default:
+			return logging.String(f.Key(), r.mask), true
+		}
+	}
+	if f.Type() == logging.GroupType {
+		children, _ := f.Value().([]logging.Field)
+		return logging.Group(f.Key(), r.Fields(children)...), true
+	}
+	s, ok := stringValue(f)
+	if !ok {
+		return f, true
+	}
+	redacted, altered, drop := r.redactString(s)
+	switch {
+	case drop:
+		return f, false
+	case f.Type() == logging.ErrorType:
+		if rewritten, ok := rewrite.Error(f.Value().(error), r.String, r.Fields); ok {
+			return logging.NamedError(f.Key(), rewritten), true
+		}
+		return f, true
+	case !altered:
+		return f, true
+	default:
+		return logging.String(f.Key(), redacted), true
+	}
+}
+
+// Fields redacts fields and returns the kept ones.
+func (r *Redactor) Fields(fields []logging.Field) []logging.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	kept := make([]logging.Field, 0, len(fields))
+	for _, f := range fields {
+		if f, ok := r.Field(f); ok {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// lazyFields redacts fields but keeps Fields of LazyType lazy.
+// Lazy Fields that should be dropped are masked instead.
+func (r *Redactor) lazyFields(fields []logging.Field) []logging.Field {
+	kept := make([]logging.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Type() != logging.LazyType {
+			if f, ok := r.Field(f); ok {
+				kept = append(kept, f)
+			}
+			continue
+		}
+		lazy := f
+		kept = append(kept, logging.LazyField(func() logging.Field {
+			resolved := logging.Resolve(lazy)
+			if f, ok := r.Field(resolved); ok {
+				return f
+			}
+			return logging.String(resolved.Key(), r.mask)
+		}))
+	}
+	return kept
+}
+
+func stringValue(f logging.Field) (string, bool) {
+	switch f.Type() {
+	case logging.StringType:
+		return logging.AttrOf(f).StringValue(), true
+	case logging.UnknownType:
+		s, ok := f.Value().(string)
+		return s, ok
+	case logging.StringerType:
+		if v, ok := f.Value().(fmt.Stringer); ok && v != nil {
+			return v.String(), true
+		}
+	case logging.ErrorType:
+		if err, ok := f.Value().(error); ok && err != nil {
+			return err.Error(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,232 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/errorx"
+)
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) Callers() []uintptr { return e.pcs }
+
+func TestRedactor_String(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+		opts []Option
+	}{
+		{
+			name: "email_mask",
+			opts: []Option{Values(Mask, Email)},
+			in:   "user foo@example.com login",
+			want: "user [REDACTED] login",
+		},
+		{
+			name: "credit_card_luhn",
+			opts: []Option{Values(Mask, CreditCard)},
+			in:   "card 4111 1111 1111 1111, order 1234567890123",
+			want: "card [REDACTED], order 1234567890123",
+		},
+		{
+			name: "bearer_keeps_scheme",
+			opts: []Option{Values(Mask, BearerToken)},
+			in:   "Authorization: Bearer abc.def-123",
+			want: "Authorization: Bearer [REDACTED]",
+		},
+		{
+			name: "jwt",
+			opts: []Option{Values(Mask, JWT), MaskWith("***")},
+			in:   "token=eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln",
+			want: "token=***",
+		},
+		{
+			name: "drop_removes_substring",
+			opts: []Option{Values(Drop, Email), Values(Mask, JWT)},
+			in:   "foo@example.com eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln",
+			want: " [REDACTED]",
+		},
+		{
+			name: "hash",
+			opts: []Option{Values(Hash, Email)},
+			in:   "foo@example.com",
+			want: New().hash("foo@example.com"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, New(tt.opts...).String(tt.in))
+		})
+	}
+}
+
+func TestRedactor_hash(t *testing.T) {
+	plain, salted := New(), New(Salt([]byte("salt")))
+	assert.Equal(t, plain.hash("a"), plain.hash("a"))
+	assert.NotEqual(t, plain.hash("a"), plain.hash("b"))
+	assert.NotEqual(t, plain.hash("a"), salted.hash("a"))
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, salted.hash("a"))
+}
+
+func TestRedactor_Field(t *testing.T) {
+	r := New(
+		Keys(Mask, "Password"),
+		Keys(Drop, "secret"),
+		KeyPattern(Hash, regexp.MustCompile(`(?i)^user_?id$`)),
+		Values(Mask, Email),
+		Values(Drop, CreditCard),
+	)
+	type result struct {
+		field logging.Field
+		keep  bool
+	}
+	tests := []struct {
+		in   logging.Field
+		want result
+		name string
+	}{
+		{
+			name: "key_mask",
+			in:   logging.Int("password", 123456),
+			want: result{logging.String("password", logging.RedactedValue), true},
+		},
+		{
+			name: "key_drop",
+			in:   logging.String("SECRET", "s"),
+			want: result{logging.String("SECRET", "s"), false},
+		},
+		{
+			name: "key_hash_non_string",
+			in:   logging.Int("userId", 1),
+			want: result{logging.String("userId", r.hash("1")), true},
+		},
+		{
+			name: "value_string",
+			in:   logging.String("msg", "to foo@example.com"),
+			want: result{logging.String("msg", "to [REDACTED]"), true},
+		},
+		{
+			name: "value_stringer",
+			in:   logging.Stringer("to", stringer("foo@example.com")),
+			want: result{logging.String("to", logging.RedactedValue), true},
+		},
+		{
+			name: "value_error_untouched",
+			in:   logging.Error(io.EOF),
+			want: result{logging.Error(io.EOF), true},
+		},
+		{
+			name: "value_error_drop",
+			in:   logging.Error(errors.New("card 4111111111111111")),
+			want: result{logging.Error(errors.New("card 4111111111111111")), false},
+		},
+		{
+			name: "value_drop",
+			in:   logging.String("card", "4111111111111111"),
+			want: result{logging.String("card", "4111111111111111"), false},
+		},
+		{
+			name: "value_untouched",
+			in:   logging.String("msg", "hello"),
+			want: result{logging.String("msg", "hello"), true},
+		},
+		{
+			name: "non_string",
+			in:   logging.Int("count", 1),
+			want: result{logging.Int("count", 1), true},
+		},
+		{
+			name: "group",
+			in: logging.Group("user",
+				logging.String("email", "foo@example.com"),
+				logging.String("secret", "s"),
+				logging.String("name", "foo"),
+			),
+			want: result{logging.Group("user",
+				logging.String("email", logging.RedactedValue),
+				logging.String("name", "foo"),
+			), true},
+		},
+		{
+			name: "lazy",
+			in:   logging.Lazy("password", func() any { return "p" }),
+			want: result{logging.String("password", logging.RedactedValue), true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, keep := r.Field(tt.in)
+			assert.Equal(t, tt.want.keep, keep)
+			assert.Equal(t, tt.want.field, f)
+		})
+	}
+}
+
+func TestRedactor_Field_error(t *testing.T) {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	cause := &stackError{msg: "bad email foo@example.com", pcs: pcs}
+	err := fmt.Errorf("send: %w", cause)
+
+	f, keep := New(Values(Mask, Email)).Field(logging.Error(err))
+	assert.True(t, keep)
+	assert.Equal(t, "error", f.Key())
+	assert.Equal(t, logging.ErrorType, f.Type())
+	got := f.Value().(error)
+	assert.Equal(t, "send: bad email [REDACTED]", got.Error())
+	assert.ErrorIs(t, got, cause)
+	gotCause := errors.Unwrap(got)
+	assert.Equal(t, "bad email [REDACTED]", gotCause.Error())
+	assert.Equal(t, pcs, logging.ErrorCallers(gotCause))
+}
+
+func TestRedactor_Field_errorFields(t *testing.T) {
+	cause := errorx.New("query", logging.String("email", "foo@example.com"), logging.String("secret", "s"))
+	err := errorx.Wrap(cause, "load", logging.String("password", "hunter2"), logging.Int("id", 1))
+
+	f, keep := New(Keys(Mask, "password"), Keys(Drop, "secret"), Values(Mask, Email)).Field(logging.Error(err))
+	assert.True(t, keep)
+	got := f.Value().(error)
+	assert.Equal(t, "load: query", got.Error())
+	assert.ErrorIs(t, got, cause)
+	assert.Equal(t, []logging.Field{
+		logging.String("password", logging.RedactedValue),
+		logging.Int("id", 1),
+		logging.String("email", logging.RedactedValue),
+	}, logging.ErrorFields(got))
+}
+
+func TestRedactor_lazyFields(t *testing.T) {
+	r := New(Keys(Drop, "secret"), Values(Mask, Email))
+	calls := 0
+	fields := r.lazyFields([]logging.Field{
+		logging.String("secret", "s"),
+		logging.Lazy("email", func() any {
+			calls++
+			return "foo@example.com"
+		}),
+		logging.Lazy("secret", func() any { return "s" }),
+	})
+	assert.Len(t, fields, 2)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, logging.String("email", logging.RedactedValue), logging.Resolve(fields[0]))
+	assert.Equal(t, logging.String("secret", logging.RedactedValue), logging.Resolve(fields[1]))
+	assert.Equal(t, 1, calls)
+}
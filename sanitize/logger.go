@@ -0,0 +1,26 @@
+package sanitize
+
+import (
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/rewrite"
+)
+
+// rewriter returns the Rewriter sanitizing via s.
+func (s *Sanitizer) rewriter() *rewrite.Rewriter {
+	return &rewrite.Rewriter{String: s.String, Fields: s.Fields, LazyFields: s.lazyFields}
+}
+
+// Logger wraps logger into a new Logger, which sanitizes messages and Fields via sanitizer
+// before passing them to logger.
+//
+// Messages of the non-w methods are formatted by the wrapper first,
+// and Fields are resolved and sanitized only if the level is enabled.
+func Logger(logger logging.Logger, sanitizer *Sanitizer) logging.Logger {
+	return rewrite.Logger(logger, sanitizer.rewriter())
+}
+
+// Sanitized creates a new Factory that produces Loggers sanitizing messages and Fields
+// via sanitizer, whichever vendor the factory is.
+func Sanitized(factory logging.Factory, sanitizer *Sanitizer) logging.Factory {
+	return rewrite.Factory(factory, sanitizer.rewriter())
+}
@@ -0,0 +1,50 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+func TestSanitized(t *testing.T) {
+	rec := logtest.New(logging.InfoLevel)
+	s := New(Escape)
+	logger := Sanitized(rec, s).Logger("test")
+
+	logger.Debug("a\nb")
+	logger.Info("a\n", "b")
+	logger.Infoln("a\nb")
+	logger.Warnf("a\n%s", "b")
+	logger.Errorw("a\nb", logging.String("k", "c\nd"))
+	logging.WithGroup(logger, "g").
+		WithField(logging.String("k", "c\nd")).
+		Errorf("%s", "ok")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 5)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/sanitize.TestSanitized", e.Caller.Function)
+	}
+	for _, e := range entries[:4] {
+		assert.Equal(t, "test", e.Name)
+		assert.Equal(t, `a\nb`, e.Message)
+	}
+	assert.Equal(t, []logging.Field{logging.String("k", `c\nd`)}, entries[3].Fields)
+	assert.Equal(t, []logging.Field{logging.Namespace("g"), logging.String("k", `c\nd`)}, entries[4].Fields)
+	assert.Equal(t, uint64(6), s.Altered())
+}
+
+func TestSanitized_disabled(t *testing.T) {
+	rec := logtest.New(logging.OffLevel)
+	s := New(Strip)
+	logger := Logger(rec, s)
+	logger.Debugf("a\n")
+	logger.Infow("a\n", logging.String("k", "a\n"))
+	logger.Warnln("a\n")
+	logger.Error("a\n")
+	assert.Empty(t, rec.Entries())
+	assert.Equal(t, uint64(0), s.Altered())
+}
@@ -0,0 +1,202 @@
+// Package sanitize provides a logger wrapper that neutralizes control characters in messages
+// and string fields, which could otherwise forge log lines in text outputs (CWE-117).
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/atomic"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/rewrite"
+)
+
+// Policy is the way unsafe characters are neutralized.
+type Policy uint8
+
+const (
+	// Escape replaces unsafe characters with their Go escape sequences, e.g. "\n" with `\n`.
+	// Backslashes are escaped as `\\` as well, so that the escaped values are unambiguous,
+	// e.g. a forged `\n` in the input never looks like an escaped newline.
+	Escape Policy = iota
+	// Strip removes unsafe characters, and whole ANSI escape sequences.
+	Strip
+)
+
+// Option configures a Sanitizer.
+type Option func(s *Sanitizer)
+
+// Allow keeps the given characters untouched, e.g. '\t'.
+func Allow(r ...rune) Option {
+	return func(s *Sanitizer) {
+		for _, c := range r {
+			s.allowed[c] = struct{}{}
+		}
+	}
+}
+
+// Sanitizer neutralizes unsafe characters, including newlines, ANSI escapes, other control
+// characters and Unicode line separators, and counts the altered values.
+// A Sanitizer is safe for concurrent use.
+type Sanitizer struct {
+	allowed map[rune]struct{}
+	altered atomic.Uint64
+	policy  Policy
+}
+
+// New creates a Sanitizer with policy and Options.
+func New(policy Policy, opts ...Option) *Sanitizer {
+	s := &Sanitizer{policy: policy, allowed: map[rune]struct{}{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Altered returns how many values, including messages, keys and Field values, have been altered.
+func (s *Sanitizer) Altered() uint64 {
+	return s.altered.Load()
+}
+
+func (s *Sanitizer) unsafe(r rune) bool {
+	if r != utf8.RuneError && !unicode.IsControl(r) && r != '\u2028' && r != '\u2029' &&
+		(r != '\\' || s.policy != Escape) {
+		return false
+	}
+	_, ok := s.allowed[r]
+	return !ok
+}
+
+// String returns the sanitized s.
+func (s *Sanitizer) String(str string) string {
+	out, altered := s.sanitize(str)
+	if altered {
+		s.altered.Inc()
+	}
+	return out
+}
+
+func (s *Sanitizer) sanitize(str string) (string, bool) {
+	i := strings.IndexFunc(str, s.unsafe)
+	if i < 0 {
+		return str, false
+	}
+	var b strings.Builder
+	b.Grow(len(str) + 8)
+	b.WriteString(str[:i])
+	for i < len(str) {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if !s.unsafe(r) {
+			b.WriteString(str[i : i+size])
+			i += size
+			continue
+		}
+		if s.policy == Strip {
+			if r == '\x1b' {
+				size = ansiLen(str[i:])
+			}
+			i += size
+			continue
+		}
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, `\x%02x`, str[i])
+		case r < utf8.RuneSelf:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+		i += size
+	}
+	return b.String(), true
+}
+
+// ansiLen returns the length of the ANSI escape sequence at the beginning of s,
+// which starts with ESC.
+func ansiLen(s string) int {
+	if len(s) < 2 || s[1] != '[' {
+		return 1
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// Field returns the sanitized f. Keys of all Fields are sanitized.
+// Values of Fields carrying strings, fmt.Stringers and errors are sanitized,
+// as well as the children of Groups. Errors are sanitized along with the errors they wrap
+// and the Fields they carry, see logging.ErrorFields, and the altered ones are replaced by wrappers
+// keeping the error trees and stacktraces.
+// Fields of LazyType are resolved before being sanitized.
+func (s *Sanitizer) Field(f logging.Field) logging.Field {
+	f = logging.Resolve(f)
+	key := s.String(f.Key())
+	switch f.Type() {
+	case logging.GroupType:
+		children, _ := f.Value().([]logging.Field)
+		return logging.Group(key, s.Fields(children)...)
+	case logging.StringType:
+		return logging.String(key, s.String(logging.AttrOf(f).StringValue()))
+	case logging.StringerType:
+		if v, ok := f.Value().(fmt.Stringer); ok && v != nil {
+			if str, altered := s.sanitize(v.String()); altered {
+				s.altered.Inc()
+				return logging.String(key, str)
+			}
+		}
+	case logging.ErrorType:
+		if err, ok := f.Value().(error); ok && err != nil {
+			if rewritten, ok := rewrite.Error(err, s.String, s.Fields); ok {
+				return logging.NamedError(key, rewritten)
+			}
+		}
+	case logging.UnknownType:
+		if v, ok := f.Value().(string); ok {
+			return logging.Any(key, s.String(v))
+		}
+	}
+	if key != f.Key() {
+		return logging.Rename(f, key)
+	}
+	return f
+}
+
+// Fields returns the sanitized fields.
+func (s *Sanitizer) Fields(fields []logging.Field) []logging.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	sanitized := make([]logging.Field, len(fields))
+	for i, f := range fields {
+		sanitized[i] = s.Field(f)
+	}
+	return sanitized
+}
+
+// lazyFields sanitizes fields but keeps Fields of LazyType lazy.
+func (s *Sanitizer) lazyFields(fields []logging.Field) []logging.Field {
+	sanitized := make([]logging.Field, len(fields))
+	for i, f := range fields {
+		if f.Type() != logging.LazyType {
+			sanitized[i] = s.Field(f)
+			continue
+		}
+		lazy := f
+		sanitized[i] = logging.LazyField(func() logging.Field { return s.Field(lazy) })
+	}
+	return sanitized
+}
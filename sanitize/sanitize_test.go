@@ -0,0 +1,235 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/errorx"
+)
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) Callers() []uintptr { return e.pcs }
+
+func TestSanitizer_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   string
+		opts   []Option
+		policy Policy
+	}{
+		{
+			name:   "safe",
+			policy: Escape,
+			in:     "hello, 世界",
+			want:   "hello, 世界",
+		},
+		{
+			name:   "escape_newline",
+			policy: Escape,
+			in:     "user\n2022-01-01 INFO admin login\r",
+			want:   `user\n2022-01-01 INFO admin login\r`,
+		},
+		{
+			name:   "escape_ansi",
+			policy: Escape,
+			in:     "\x1b[31mred\x1b[0m",
+			want:   `\x1b[31mred\x1b[0m`,
+		},
+		{
+			name:   "escape_unicode",
+			policy: Escape,
+			in:     "a\u2028b\u0085c\xffd",
+			want:   `a\u2028b\u0085c\xffd`,
+		},
+		{
+			name:   "escape_tab",
+			policy: Escape,
+			in:     "a\tb",
+			want:   `a\tb`,
+		},
+		{
+			name:   "escape_backslash",
+			policy: Escape,
+			in:     `C:\tmp\n` + "\n",
+			want:   `C:\\tmp\\n\n`,
+		},
+		{
+			name:   "allow_backslash",
+			policy: Escape,
+			opts:   []Option{Allow('\\')},
+			in:     `a\b`,
+			want:   `a\b`,
+		},
+		{
+			name:   "strip_keeps_backslash",
+			policy: Strip,
+			in:     `a\b`,
+			want:   `a\b`,
+		},
+		{
+			name:   "allow_tab",
+			policy: Escape,
+			opts:   []Option{Allow('\t')},
+			in:     "a\tb\n",
+			want:   "a\tb\\n",
+		},
+		{
+			name:   "strip",
+			policy: Strip,
+			in:     "a\nb\x00c d",
+			want:   "abcd",
+		},
+		{
+			name:   "strip_ansi",
+			policy: Strip,
+			in:     "\x1b[1;31mred\x1b[0m\x1b",
+			want:   "red",
+		},
+		{
+			name:   "strip_unterminated_ansi",
+			policy: Strip,
+			in:     "red\x1b[1;3",
+			want:   "red",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, New(tt.policy, tt.opts...).String(tt.in))
+		})
+	}
+}
+
+func TestSanitizer_Altered(t *testing.T) {
+	s := New(Strip)
+	s.String("safe")
+	assert.Equal(t, uint64(0), s.Altered())
+	s.String("a\nb")
+	s.String("a\nb\nc")
+	assert.Equal(t, uint64(2), s.Altered())
+}
+
+func TestSanitizer_Field(t *testing.T) {
+	tests := []struct {
+		in      logging.Field
+		want    logging.Field
+		name    string
+		altered uint64
+	}{
+		{
+			name:    "string",
+			in:      logging.String("k", "a\nb"),
+			want:    logging.String("k", `a\nb`),
+			altered: 1,
+		},
+		{
+			name:    "key",
+			in:      logging.Int("k\n", 1),
+			want:    logging.Int(`k\n`, 1),
+			altered: 1,
+		},
+		{
+			name:    "stringer",
+			in:      logging.Stringer("k", stringer("a\nb")),
+			want:    logging.String("k", `a\nb`),
+			altered: 1,
+		},
+		{
+			name: "safe_stringer",
+			in:   logging.Stringer("k", stringer("ab")),
+			want: logging.Stringer("k", stringer("ab")),
+		},
+		{
+			name: "safe_error",
+			in:   logging.Error(io.EOF),
+			want: logging.Error(io.EOF),
+		},
+		{
+			name:    "unknown_string",
+			in:      logging.Any("k", "a\nb"),
+			want:    logging.Any("k", `a\nb`),
+			altered: 1,
+		},
+		{
+			name:    "group",
+			in:      logging.Group("g", logging.String("k", "a\nb"), logging.Int("n", 1)),
+			want:    logging.Group("g", logging.String("k", `a\nb`), logging.Int("n", 1)),
+			altered: 1,
+		},
+		{
+			name:    "lazy",
+			in:      logging.Lazy("k", func() any { return "a\nb" }),
+			want:    logging.String("k", `a\nb`),
+			altered: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Escape)
+			assert.Equal(t, tt.want, s.Field(tt.in))
+			assert.Equal(t, tt.altered, s.Altered())
+		})
+	}
+}
+
+func TestSanitizer_Field_error(t *testing.T) {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	stack := &stackError{msg: "stack\r", pcs: pcs}
+	cause := errorx.Wrap(stack, "cause", logging.String("k", "v\n"))
+	joined := errors.Join(io.EOF, cause)
+	err := fmt.Errorf("load: %w", joined)
+
+	s := New(Escape)
+	f := s.Field(logging.Error(err))
+	assert.Equal(t, "error", f.Key())
+	assert.Equal(t, logging.ErrorType, f.Type())
+	got := f.Value().(error)
+	assert.Equal(t, `load: EOF\ncause: stack\r`, got.Error())
+	assert.ErrorIs(t, got, io.EOF)
+	assert.ErrorIs(t, got, cause)
+	assert.ErrorIs(t, got, stack)
+	assert.Equal(t, []logging.Field{logging.String("k", `v\n`)}, logging.ErrorFields(got))
+
+	causes := errors.Unwrap(got).(interface{ Unwrap() []error }).Unwrap()
+	assert.Len(t, causes, 2)
+	assert.Equal(t, io.EOF, causes[0])
+	assert.Equal(t, `cause: stack\r`, causes[1].Error())
+	gotStack := errors.Unwrap(causes[1])
+	assert.Equal(t, `stack\r`, gotStack.Error())
+	assert.Equal(t, pcs, logging.ErrorCallers(gotStack))
+	// the messages of err, joined, cause and stack, and the value carried by cause.
+	assert.Equal(t, uint64(5), s.Altered())
+}
+
+func TestSanitizer_lazyFields(t *testing.T) {
+	s := New(Escape)
+	calls := 0
+	fields := s.lazyFields([]logging.Field{
+		logging.String("k", "a\nb"),
+		logging.Lazy("l", func() any {
+			calls++
+			return "c\nd"
+		}),
+	})
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, logging.String("k", `a\nb`), fields[0])
+	assert.Equal(t, logging.String("l", `c\nd`), logging.Resolve(fields[1]))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, uint64(2), s.Altered())
+}
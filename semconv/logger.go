@@ -0,0 +1,155 @@
+package semconv
+
+import (
+	"github.com/yimi-go/logging"
+)
+
+// ViolationMessage is the message of the warning logs reporting Violations.
+const ViolationMessage = "semantic convention violated"
+
+type validated struct {
+	logger    logging.Logger
+	validator *Validator
+	prefix    string
+}
+
+// Logger wraps logger into a new Logger, which validates Fields via validator before passing
+// them to logger. Fields are resolved and validated only if the level is enabled.
+//
+// Unless the validator reports Violations via the function set via OnViolation, the Logger reports
+// them by warning logs via logger, with ViolationMessage and a "violation" Field describing them.
+func Logger(logger logging.Logger, validator *Validator) logging.Logger {
+	// skip the wrapper methods to report the real callers.
+	return &validated{logger: logging.AddCallerSkip(logger, 1), validator: validator}
+}
+
+func (l *validated) fields(fields []logging.Field) ([]logging.Field, []Violation) {
+	var violations []Violation
+	kept, _ := l.validator.fields(l.prefix, fields, l.validator.reporter(func(v Violation) {
+		violations = append(violations, v)
+	}))
+	return kept, violations
+}
+
+func (l *validated) warn(violations []Violation) {
+	if len(violations) == 0 {
+		return
+	}
+	// skip this method as well.
+	logger := logging.AddCallerSkip(l.logger, 1)
+	for _, v := range violations {
+		logger.Warnw(ViolationMessage, logging.String("violation", v.String()))
+	}
+}
+
+func (l *validated) Enabled(lvl logging.Level) bool {
+	return l.logger.Enabled(lvl)
+}
+func (l *validated) Debug(v ...any) {
+	l.logger.Debug(v...)
+}
+func (l *validated) Debugln(v ...any) {
+	l.logger.Debugln(v...)
+}
+func (l *validated) Debugf(format string, v ...any) {
+	l.logger.Debugf(format, v...)
+}
+func (l *validated) Debugw(message string, field ...logging.Field) {
+	if l.Enabled(logging.DebugLevel) {
+		kept, violations := l.fields(field)
+		l.logger.Debugw(message, kept...)
+		l.warn(violations)
+	}
+}
+func (l *validated) Info(v ...any) {
+	l.logger.Info(v...)
+}
+func (l *validated) Infoln(v ...any) {
+	l.logger.Infoln(v...)
+}
+func (l *validated) Infof(format string, v ...any) {
+	l.logger.Infof(format, v...)
+}
+func (l *validated) Infow(message string, field ...logging.Field) {
+	if l.Enabled(logging.InfoLevel) {
+		kept, violations := l.fields(field)
+		l.logger.Infow(message, kept...)
+		l.warn(violations)
+	}
+}
+func (l *validated) Warn(v ...any) {
+	l.logger.Warn(v...)
+}
+func (l *validated) Warnln(v ...any) {
+	l.logger.Warnln(v...)
+}
+func (l *validated) Warnf(format string, v ...any) {
+	l.logger.Warnf(format, v...)
+}
+func (l *validated) Warnw(message string, field ...logging.Field) {
+	if l.Enabled(logging.WarnLevel) {
+		kept, violations := l.fields(field)
+		l.logger.Warnw(message, kept...)
+		l.warn(violations)
+	}
+}
+func (l *validated) Error(v ...any) {
+	l.logger.Error(v...)
+}
+func (l *validated) Errorln(v ...any) {
+	l.logger.Errorln(v...)
+}
+func (l *validated) Errorf(format string, v ...any) {
+	l.logger.Errorf(format, v...)
+}
+func (l *validated) Errorw(message string, field ...logging.Field) {
+	if l.Enabled(logging.ErrorLevel) {
+		kept, violations := l.fields(field)
+		l.logger.Errorw(message, kept...)
+		l.warn(violations)
+	}
+}
+func (l *validated) WithField(field ...logging.Field) logging.Logger {
+	var violations []Violation
+	kept, prefix := l.validator.lazyFields(l.prefix, field, l.validator.reporter(func(v Violation) {
+		violations = append(violations, v)
+	}))
+	l.warn(violations)
+	return &validated{
+		logger:    l.logger.WithField(kept...),
+		validator: l.validator,
+		prefix:    prefix,
+	}
+}
+func (l *validated) WithGroup(name string) logging.Logger {
+	return &validated{
+		logger:    logging.WithGroup(l.logger, name),
+		validator: l.validator,
+		prefix:    join(l.prefix, name),
+	}
+}
+func (l *validated) WithCallerSkip(skip int) logging.Logger {
+	return &validated{
+		logger:    logging.AddCallerSkip(l.logger, skip),
+		validator: l.validator,
+		prefix:    l.prefix,
+	}
+}
+
+type validatedFactory struct {
+	factory   logging.Factory
+	validator *Validator
+}
+
+func (f *validatedFactory) Logger(name string) logging.Logger {
+	return Logger(f.factory.Logger(name), f.validator)
+}
+
+// Validated creates a new Factory that produces Loggers validating Fields via validator,
+// whichever vendor the factory is.
+func Validated(factory logging.Factory, validator *Validator) logging.Factory {
+	return &validatedFactory{
+		factory:   factory,
+		validator: validator,
+	}
+}
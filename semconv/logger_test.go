@@ -0,0 +1,77 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+func TestValidated(t *testing.T) {
+	rec := logtest.New(logging.InfoLevel)
+	logger := Validated(rec, NewValidator(OTel, Reject)).Logger("test")
+
+	logger.Debugw("debug", logging.String("http.response.status_code", "200"))
+	logger.Info("info")
+	logger.Infoln("info")
+	logger.Warnf("warn %d", 1)
+	logger.Error("error")
+	logging.WithGroup(logger, "http").
+		WithField(logging.String("response.status_code", "200"), logging.String("route", "/")).
+		Infow("request", logging.Int("response.status_code", 200))
+	logger.Infow("url", logging.String("url.original", "/"))
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 8)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/semconv.TestValidated", e.Caller.Function)
+		assert.Equal(t, "test", e.Name)
+	}
+	assert.Equal(t, "info", entries[0].Message)
+	assert.Equal(t, "warn 1", entries[2].Message)
+	assert.Equal(t, logging.ErrorLevel, entries[3].Level)
+	assert.Equal(t, ViolationMessage, entries[4].Message)
+	assert.Equal(t, logging.WarnLevel, entries[4].Level)
+	assert.Equal(t, []logging.Field{
+		logging.Namespace("http"),
+		logging.String("violation", `key "http.response.status_code" expects int values in OTel`),
+	}, entries[4].Fields)
+	assert.Equal(t, "request", entries[5].Message)
+	assert.Equal(t, []logging.Field{
+		logging.Namespace("http"),
+		logging.String("route", "/"),
+		logging.Int("response.status_code", 200),
+	}, entries[5].Fields)
+	assert.Empty(t, entries[6].Fields)
+	assert.Equal(t, []logging.Field{
+		logging.String("violation", `key "url.original" should be "url.full" in OTel`),
+	}, entries[7].Fields)
+}
+
+func TestValidated_lazy(t *testing.T) {
+	rec := logtest.New(logging.InfoLevel)
+	var reported []Violation
+	v := NewValidator(OTel, Reject, OnViolation(func(v Violation) { reported = append(reported, v) }))
+	calls := 0
+	lazy := logging.Lazy("user.id", func() any {
+		calls++
+		return 1
+	})
+	logger := Logger(rec, v)
+	logger.Debugw("debug", lazy)
+	assert.Zero(t, calls)
+
+	logger = logger.WithField(lazy, logging.Lazy("user.name", func() any { return "n" }))
+	assert.Zero(t, calls)
+	logger.Infow("info")
+	entries := rec.Entries()
+	assert.Len(t, entries, 1)
+	assert.Len(t, entries[0].Fields, 2)
+	assert.Equal(t, logging.Group(""), logging.Resolve(entries[0].Fields[0]))
+	assert.Equal(t, logging.String("user.name", "n"), logging.Resolve(entries[0].Fields[1]))
+	assert.Equal(t, 1, calls)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, "user.id", reported[0].Key)
+}
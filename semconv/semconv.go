@@ -0,0 +1,273 @@
+// Package semconv provides well-known Field keys named after both the Elastic Common Schema
+// and the OpenTelemetry semantic conventions, and a logger wrapper validating Fields against them,
+// so that services log the same things with the same keys and types.
+package semconv
+
+import (
+	"strings"
+
+	"go.uber.org/atomic"
+
+	"github.com/yimi-go/logging"
+)
+
+// Schema is a naming convention of Field keys.
+type Schema uint32
+
+const (
+	// OTel is the OpenTelemetry semantic conventions, e.g. "http.request.method" and "url.full".
+	OTel Schema = iota
+	// ECS is the Elastic Common Schema, e.g. "http.request.method" and "url.original".
+	ECS
+)
+
+// String returns the name of the Schema.
+func (s Schema) String() string {
+	if s == ECS {
+		return "ECS"
+	}
+	return "OTel"
+}
+
+var schemaStore = atomic.NewUint32(uint32(OTel))
+
+// GetSchema returns the Schema of the process, OTel by default.
+func GetSchema() Schema {
+	return Schema(schemaStore.Load())
+}
+
+// SwapSchema sets the Schema of the process, and returns the origin Schema.
+// It is expected to be called at initialization.
+func SwapSchema(schema Schema) Schema {
+	return Schema(schemaStore.Swap(uint32(schema)))
+}
+
+// Kind is the kind of values a Key expects.
+type Kind uint8
+
+const (
+	// StringKind accepts Fields of StringType and StringerType.
+	StringKind Kind = iota
+	// IntKind accepts Fields of the signed and unsigned integer FieldTypes.
+	IntKind
+	// FloatKind accepts Fields of Float64Type and Float32Type.
+	FloatKind
+	// BoolKind accepts Fields of BoolType.
+	BoolKind
+)
+
+var kindNames = [...]string{"string", "int", "float", "bool"}
+
+// String returns the name of the Kind.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "unknown"
+}
+
+// Accepts reports whether a Field of typ carries values of the Kind.
+func (k Kind) Accepts(typ logging.FieldType) bool {
+	switch typ {
+	case logging.StringType, logging.StringerType:
+		return k == StringKind
+	case logging.Int64Type, logging.Int32Type, logging.Int16Type, logging.Int8Type,
+		logging.Uint64Type, logging.Uint32Type, logging.Uint16Type, logging.Uint8Type:
+		return k == IntKind
+	case logging.Float64Type, logging.Float32Type:
+		return k == FloatKind
+	case logging.BoolType:
+		return k == BoolKind
+	default:
+		return false
+	}
+}
+
+// Key is a well-known Field key, named differently by Schemas.
+type Key struct {
+	ecs, otel         string
+	ecsKind, otelKind Kind
+}
+
+// NewKey creates a Key named ecs in ECS and otel in OTel, expecting values of kind in both.
+// An empty name means the Key is not defined by the Schema.
+func NewKey(ecs, otel string, kind Kind) Key {
+	return Key{ecs: ecs, otel: otel, ecsKind: kind, otelKind: kind}
+}
+
+// NewKeyKinds is like NewKey but the Schemas expect values of different kinds,
+// e.g. durations are integer nanoseconds in ECS but float seconds in OTel.
+func NewKeyKinds(ecs string, ecsKind Kind, otel string, otelKind Kind) Key {
+	return Key{ecs: ecs, otel: otel, ecsKind: ecsKind, otelKind: otelKind}
+}
+
+// Name returns the name of the Key in schema.
+func (k Key) Name(schema Schema) string {
+	if schema == ECS {
+		return k.ecs
+	}
+	return k.otel
+}
+
+// Kind returns the Kind of values the Key expects in schema.
+func (k Key) Kind(schema Schema) Kind {
+	if schema == ECS {
+		return k.ecsKind
+	}
+	return k.otelKind
+}
+
+// String returns the name of the Key in the Schema set via SwapSchema.
+func (k Key) String() string {
+	return k.Name(GetSchema())
+}
+
+// Well-known service keys.
+var (
+	ServiceName        = NewKey("service.name", "service.name", StringKind)
+	ServiceVersion     = NewKey("service.version", "service.version", StringKind)
+	ServiceEnvironment = NewKey("service.environment", "deployment.environment.name", StringKind)
+	ServiceInstanceID  = NewKey("service.node.name", "service.instance.id", StringKind)
+)
+
+// Well-known trace keys.
+var (
+	TraceID = NewKey("trace.id", "trace_id", StringKind)
+	SpanID  = NewKey("span.id", "span_id", StringKind)
+)
+
+// Well-known HTTP keys.
+var (
+	HTTPRequestMethod      = NewKey("http.request.method", "http.request.method", StringKind)
+	HTTPRoute              = NewKey("http.route", "http.route", StringKind)
+	HTTPVersion            = NewKey("http.version", "network.protocol.version", StringKind)
+	HTTPResponseStatusCode = NewKey("http.response.status_code", "http.response.status_code", IntKind)
+	HTTPResponseBodySize   = NewKey("http.response.body.bytes", "http.response.body.size", IntKind)
+	HTTPServerDuration     = NewKeyKinds("event.duration", IntKind, "http.server.request.duration", FloatKind)
+	HTTPClientDuration     = NewKeyKinds("event.duration", IntKind, "http.client.request.duration", FloatKind)
+	HTTPRequestResendCount = NewKey("", "http.request.resend_count", IntKind)
+	NetworkProtocolName    = NewKey("network.protocol", "network.protocol.name", StringKind)
+	URLFull                = NewKey("url.original", "url.full", StringKind)
+	URLPath                = NewKey("url.path", "url.path", StringKind)
+	UserAgent              = NewKey("user_agent.original", "user_agent.original", StringKind)
+	ClientAddress          = NewKey("source.address", "client.address", StringKind)
+	ClientPort             = NewKey("source.port", "client.port", IntKind)
+	ServerAddress          = NewKey("destination.address", "server.address", StringKind)
+)
+
+// Well-known database keys.
+var (
+	DBSystem    = NewKey("db.type", "db.system.name", StringKind)
+	DBNamespace = NewKey("db.instance", "db.namespace", StringKind)
+	DBStatement = NewKey("db.statement", "db.query.text", StringKind)
+	DBOperation = NewKey("db.operation", "db.operation.name", StringKind)
+)
+
+// Well-known error keys.
+var (
+	ErrorType       = NewKey("error.type", "error.type", StringKind)
+	ErrorMessage    = NewKey("error.message", "exception.message", StringKind)
+	ErrorStackTrace = NewKey("error.stack_trace", "exception.stacktrace", StringKind)
+)
+
+// Well-known user keys.
+var (
+	UserID    = NewKey("user.id", "user.id", StringKind)
+	UserName  = NewKey("user.name", "user.name", StringKind)
+	UserEmail = NewKey("user.email", "user.email", StringKind)
+)
+
+// Well-known event keys.
+var (
+	EventName     = NewKey("event.action", "event.name", StringKind)
+	EventOutcome  = NewKey("event.outcome", "", StringKind)
+	EventDuration = NewKeyKinds("event.duration", IntKind, "", IntKind)
+)
+
+// Keys returns all the well-known Keys of this package.
+func Keys() []Key {
+	return []Key{
+		ServiceName, ServiceVersion, ServiceEnvironment, ServiceInstanceID,
+		TraceID, SpanID,
+		HTTPRequestMethod, HTTPRoute, HTTPVersion, HTTPResponseStatusCode, HTTPResponseBodySize,
+		HTTPServerDuration, HTTPClientDuration, HTTPRequestResendCount,
+		NetworkProtocolName, URLFull, URLPath, UserAgent, ClientAddress, ClientPort, ServerAddress,
+		DBSystem, DBNamespace, DBStatement, DBOperation,
+		ErrorType, ErrorMessage, ErrorStackTrace,
+		UserID, UserName, UserEmail,
+		EventName, EventOutcome, EventDuration,
+	}
+}
+
+// Nest nests fields keyed by dotted names, e.g. the names of Keys, into Groups by the segments
+// of the names, so that String("http.request.method", "GET") results
+// Group("http", Group("request", String("method", "GET"))).
+//
+// Fields sharing prefixes are put into the same Groups, in the order the prefixes first appear,
+// and the children of Fields of GroupType are merged into the Groups with the same keys,
+// e.g. for adding the Fields of a response to the "http" Group of a request.
+// The keys of the children of Fields of GroupType are not split.
+func Nest(fields ...logging.Field) []logging.Field {
+	var root nestNode
+	for _, f := range fields {
+		root.add(f, true)
+	}
+	return root.fields()
+}
+
+// nestNode is a Group being built by Nest, or a leaf Field if group is false.
+type nestNode struct {
+	field    logging.Field
+	groups   map[string]*nestNode
+	key      string
+	children []*nestNode
+	group    bool
+}
+
+func (n *nestNode) add(f logging.Field, split bool) {
+	key := f.Key()
+	if split {
+		for i := strings.IndexByte(key, '.'); i >= 0; i = strings.IndexByte(key, '.') {
+			n = n.child(key[:i])
+			key = key[i+1:]
+		}
+	}
+	if f.Type() == logging.GroupType {
+		g := n.child(key)
+		children, _ := f.Value().([]logging.Field)
+		for _, c := range children {
+			g.add(c, false)
+		}
+		return
+	}
+	if key != f.Key() {
+		f = logging.Rename(f, key)
+	}
+	n.children = append(n.children, &nestNode{field: f})
+}
+
+// child returns the Group keyed by key, which is added if missing.
+func (n *nestNode) child(key string) *nestNode {
+	if g, ok := n.groups[key]; ok {
+		return g
+	}
+	g := &nestNode{key: key, group: true}
+	if n.groups == nil {
+		n.groups = make(map[string]*nestNode)
+	}
+	n.groups[key] = g
+	n.children = append(n.children, g)
+	return g
+}
+
+func (n *nestNode) fields() []logging.Field {
+	fields := make([]logging.Field, len(n.children))
+	for i, c := range n.children {
+		if c.group {
+			fields[i] = logging.Group(c.key, c.fields()...)
+		} else {
+			fields[i] = c.field
+		}
+	}
+	return fields
+}
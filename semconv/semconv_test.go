@@ -0,0 +1,67 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+)
+
+func TestSwapSchema(t *testing.T) {
+	assert.Equal(t, OTel, GetSchema())
+	assert.Equal(t, "url.full", URLFull.String())
+	assert.Equal(t, OTel, SwapSchema(ECS))
+	assert.Equal(t, "url.original", URLFull.String())
+	assert.Equal(t, ECS, SwapSchema(OTel))
+	assert.Equal(t, "ECS", ECS.String())
+	assert.Equal(t, "OTel", OTel.String())
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "event.duration", HTTPServerDuration.Name(ECS))
+	assert.Equal(t, IntKind, HTTPServerDuration.Kind(ECS))
+	assert.Equal(t, "http.server.request.duration", HTTPServerDuration.Name(OTel))
+	assert.Equal(t, FloatKind, HTTPServerDuration.Kind(OTel))
+	assert.Equal(t, "", EventOutcome.Name(OTel))
+	for _, k := range Keys() {
+		assert.True(t, k.Name(ECS) != "" || k.Name(OTel) != "")
+	}
+}
+
+func TestKind(t *testing.T) {
+	assert.Equal(t, "string", StringKind.String())
+	assert.Equal(t, "bool", BoolKind.String())
+	assert.Equal(t, "unknown", Kind(255).String())
+	assert.True(t, StringKind.Accepts(logging.StringType))
+	assert.True(t, StringKind.Accepts(logging.StringerType))
+	assert.False(t, StringKind.Accepts(logging.Int64Type))
+	assert.True(t, IntKind.Accepts(logging.Uint16Type))
+	assert.False(t, IntKind.Accepts(logging.StringType))
+	assert.True(t, FloatKind.Accepts(logging.Float32Type))
+	assert.True(t, BoolKind.Accepts(logging.BoolType))
+	assert.False(t, BoolKind.Accepts(logging.UnknownType))
+}
+
+func TestNest(t *testing.T) {
+	assert.Empty(t, Nest())
+	assert.Equal(t, []logging.Field{
+		logging.Group("http",
+			logging.Group("request",
+				logging.String("method", "GET"),
+				logging.Group("header", logging.String("a.b", "v")),
+			),
+			logging.Int("status", 200),
+			logging.Group("response", logging.Int("status_code", 200)),
+		),
+		logging.String("error", "e"),
+		logging.Group("url", logging.String("path", "/")),
+	}, Nest(
+		logging.String("http.request.method", "GET"),
+		logging.String("error", "e"),
+		logging.Group("http.request.header", logging.String("a.b", "v")),
+		logging.Group("url", logging.String("path", "/")),
+		logging.Group("http", logging.Int("status", 200), logging.Group("response")),
+		logging.Int("http.response.status_code", 200),
+	))
+}
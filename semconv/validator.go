@@ -0,0 +1,245 @@
+package semconv
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yimi-go/logging"
+)
+
+// Mode is the way a Validator handles Fields violating the Schema.
+type Mode uint8
+
+const (
+	// Warn keeps the violating Fields and reports the Violations.
+	Warn Mode = iota
+	// Reject drops the violating Fields and reports the Violations.
+	Reject
+)
+
+// Violation describes a Field contradicting the Schema.
+type Violation struct {
+	// Key is the full dotted key of the Field, including the Group names.
+	Key string
+	// Want is the name of the Key in the Schema, if the Field is keyed by the name of another Schema.
+	Want string
+	// Type is the FieldType of the Field.
+	Type logging.FieldType
+	// Kind is the Kind the Schema expects for the key.
+	Kind Kind
+	// Schema is the Schema validated against.
+	Schema Schema
+}
+
+// String describes the Violation.
+func (v Violation) String() string {
+	if v.Want != "" {
+		return fmt.Sprintf("key %q should be %q in %s", v.Key, v.Want, v.Schema)
+	}
+	return fmt.Sprintf("key %q expects %s values in %s", v.Key, v.Kind, v.Schema)
+}
+
+// Option configures a Validator.
+type Option func(v *Validator)
+
+// WithKeys adds Keys to validate besides the well-known Keys of this package.
+func WithKeys(keys ...Key) Option {
+	return func(v *Validator) {
+		v.addKeys(keys)
+	}
+}
+
+// OnViolation sets the function reporting Violations.
+// By default, the Logger wrappers report Violations by warning logs.
+func OnViolation(report func(Violation)) Option {
+	return func(v *Validator) {
+		v.report = report
+	}
+}
+
+// Validator validates Fields against a Schema: a Field violates the Schema if its key is
+// the name of a Key in another Schema, or if its FieldType does not match the Kind of the Key.
+// Keys unknown to the Validator are not validated.
+//
+// A Validator is immutable and safe for concurrent use.
+type Validator struct {
+	report func(Violation)
+	keys   map[string]Key
+	others map[string]Key
+	schema Schema
+	mode   Mode
+}
+
+// NewValidator creates a Validator of schema handling violations in mode.
+func NewValidator(schema Schema, mode Mode, opts ...Option) *Validator {
+	v := &Validator{
+		keys:   map[string]Key{},
+		others: map[string]Key{},
+		schema: schema,
+		mode:   mode,
+	}
+	v.addKeys(Keys())
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+func (v *Validator) addKeys(keys []Key) {
+	other := OTel
+	if v.schema == OTel {
+		other = ECS
+	}
+	for _, k := range keys {
+		if name := k.Name(v.schema); name != "" {
+			v.keys[name] = k
+		}
+		if name := k.Name(other); name != "" {
+			v.others[name] = k
+		}
+	}
+}
+
+// Check reports the Violation of a Field keyed by key, with FieldType typ and value.
+// The value is used to infer the Kind of Fields of UnknownType, and could be nil otherwise.
+func (v *Validator) Check(key string, typ logging.FieldType, value any) (Violation, bool) {
+	if k, ok := v.keys[key]; ok {
+		kind := k.Kind(v.schema)
+		if typ == logging.UnknownType {
+			if value == nil || kindOf(value) == kind {
+				return Violation{}, false
+			}
+		} else if kind.Accepts(typ) {
+			return Violation{}, false
+		}
+		return Violation{Key: key, Type: typ, Kind: kind, Schema: v.schema}, true
+	}
+	if k, ok := v.others[key]; ok {
+		if want := k.Name(v.schema); want != key {
+			return Violation{Key: key, Want: want, Type: typ, Kind: k.Kind(v.schema), Schema: v.schema}, true
+		}
+	}
+	return Violation{}, false
+}
+
+// Fields validates fields, reports the Violations via report, or the function set via OnViolation
+// if any, and returns the Fields to keep according to the Mode.
+//
+// Fields of LazyType are resolved. Children of Groups are validated with the Group keys
+// prefixed, as are the Fields following a Namespace.
+func (v *Validator) Fields(fields []logging.Field, report func(Violation)) []logging.Field {
+	kept, _ := v.fields("", fields, v.reporter(report))
+	return kept
+}
+
+func (v *Validator) reporter(report func(Violation)) func(Violation) {
+	if v.report != nil {
+		return v.report
+	}
+	return report
+}
+
+func (v *Validator) fields(prefix string, fields []logging.Field, report func(Violation)) ([]logging.Field, bool) {
+	kept := fields
+	altered := false
+	for i, f := range fields {
+		resolved := logging.Resolve(f)
+		if resolved.Type() == logging.NamespaceType {
+			prefix = join(prefix, resolved.Key())
+		}
+		validated, ok, changed := v.field(prefix, resolved, report)
+		changed = changed || f.Type() == logging.LazyType
+		if ok && !changed && !altered {
+			continue
+		}
+		if !altered {
+			kept = make([]logging.Field, i, len(fields))
+			copy(kept, fields[:i])
+			altered = true
+		}
+		if ok {
+			kept = append(kept, validated)
+		}
+	}
+	return kept, altered
+}
+
+// field validates a resolved Field, and reports whether it should be kept and whether
+// it is changed, i.e. its children are rejected.
+func (v *Validator) field(prefix string, f logging.Field, report func(Violation)) (logging.Field, bool, bool) {
+	switch f.Type() {
+	case logging.NamespaceType:
+		return f, true, false
+	case logging.GroupType:
+		children, _ := f.Value().([]logging.Field)
+		kept, altered := v.fields(join(prefix, f.Key()), children, report)
+		if !altered {
+			return f, true, false
+		}
+		return logging.Group(f.Key(), kept...), true, true
+	}
+	encoded := logging.Encode(f)
+	violation, ok := v.Check(join(prefix, f.Key()), encoded.Type(), encoded.Interface())
+	if !ok {
+		return f, true, false
+	}
+	if report != nil {
+		report(violation)
+	}
+	return f, v.mode != Reject, false
+}
+
+// lazyFields validates fields but keeps Fields of LazyType lazy, and returns the prefix
+// of the subsequent Fields. Lazy Fields that should be rejected are replaced with empty Groups.
+// Violations of lazy Fields are only reported via the function set via OnViolation, since
+// they are resolved by vendors in the middle of outputting logs.
+func (v *Validator) lazyFields(prefix string, fields []logging.Field, report func(Violation)) ([]logging.Field, string) {
+	kept := make([]logging.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Type() != logging.LazyType {
+			if f.Type() == logging.NamespaceType {
+				prefix = join(prefix, f.Key())
+			}
+			if f, ok, _ := v.field(prefix, f, report); ok {
+				kept = append(kept, f)
+			}
+			continue
+		}
+		lazy, lazyPrefix := f, prefix
+		kept = append(kept, logging.LazyField(func() logging.Field {
+			if f, ok, _ := v.field(lazyPrefix, logging.Resolve(lazy), v.report); ok {
+				return f
+			}
+			return logging.Group("")
+		}))
+	}
+	return kept, prefix
+}
+
+func join(prefix, key string) string {
+	switch {
+	case key == "":
+		return prefix
+	case prefix == "":
+		return key
+	default:
+		return prefix + "." + key
+	}
+}
+
+// kindOf infers the Kind of value carried by a Field of UnknownType.
+func kindOf(value any) Kind {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return IntKind
+	case reflect.Float64, reflect.Float32:
+		return FloatKind
+	case reflect.Bool:
+		return BoolKind
+	case reflect.String:
+		return StringKind
+	default:
+		return Kind(255)
+	}
+}
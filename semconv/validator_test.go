@@ -0,0 +1,89 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+)
+
+func TestValidator_Check(t *testing.T) {
+	v := NewValidator(OTel, Warn)
+	_, bad := v.Check("http.response.status_code", logging.Int64Type, nil)
+	assert.False(t, bad)
+	_, bad = v.Check("http.response.status_code", logging.UnknownType, 200)
+	assert.False(t, bad)
+	_, bad = v.Check("http.response.status_code", logging.UnknownType, nil)
+	assert.False(t, bad)
+	_, bad = v.Check("custom", logging.BoolType, nil)
+	assert.False(t, bad)
+	_, bad = v.Check("http.response.body.bytes", logging.Int64Type, nil)
+	assert.True(t, bad)
+
+	violation, bad := v.Check("http.response.status_code", logging.StringType, nil)
+	assert.True(t, bad)
+	assert.Equal(t, Violation{Key: "http.response.status_code", Type: logging.StringType, Kind: IntKind, Schema: OTel}, violation)
+	assert.Equal(t, `key "http.response.status_code" expects int values in OTel`, violation.String())
+	_, bad = v.Check("http.response.status_code", logging.UnknownType, "200")
+	assert.True(t, bad)
+
+	violation, bad = v.Check("url.original", logging.StringType, nil)
+	assert.True(t, bad)
+	assert.Equal(t, `key "url.original" should be "url.full" in OTel`, violation.String())
+
+	v = NewValidator(ECS, Warn, WithKeys(NewKey("order.id", "order.id", IntKind)))
+	_, bad = v.Check("url.original", logging.StringType, nil)
+	assert.False(t, bad)
+	_, bad = v.Check("event.duration", logging.Float64Type, nil)
+	assert.True(t, bad)
+	_, bad = v.Check("order.id", logging.StringType, nil)
+	assert.True(t, bad)
+	_, bad = v.Check("event.name", logging.StringType, nil)
+	assert.True(t, bad)
+}
+
+func TestValidator_Fields(t *testing.T) {
+	var violations []Violation
+	report := func(v Violation) { violations = append(violations, v) }
+	fields := []logging.Field{
+		logging.String("user.id", "u"),
+		logging.Group("http",
+			logging.Group("response", logging.String("status_code", "200")),
+			logging.String("request.method", "GET"),
+		),
+		logging.Lazy("user.name", func() any { return 1 }),
+		logging.Namespace("http"),
+		logging.Int("route", 1),
+	}
+
+	kept := NewValidator(OTel, Warn).Fields(fields, report)
+	assert.Len(t, violations, 3)
+	assert.Equal(t, "http.response.status_code", violations[0].Key)
+	assert.Equal(t, "user.name", violations[1].Key)
+	assert.Equal(t, "http.route", violations[2].Key)
+	assert.Equal(t, logging.Int("user.name", 1), kept[2])
+	assert.Equal(t, fields[:2], kept[:2])
+
+	violations = nil
+	kept = NewValidator(OTel, Reject).Fields(fields, report)
+	assert.Len(t, violations, 3)
+	assert.Equal(t, []logging.Field{
+		logging.String("user.id", "u"),
+		logging.Group("http",
+			logging.Group("response"),
+			logging.String("request.method", "GET"),
+		),
+		logging.Namespace("http"),
+	}, kept)
+
+	valid := fields[:1]
+	assert.Equal(t, valid, NewValidator(OTel, Reject).Fields(valid, report))
+
+	var reported []Violation
+	v := NewValidator(OTel, Reject, OnViolation(func(v Violation) { reported = append(reported, v) }))
+	violations = nil
+	v.Fields(fields, report)
+	assert.Empty(t, violations)
+	assert.Len(t, reported, 3)
+}
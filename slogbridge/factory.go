@@ -0,0 +1,297 @@
+package slogbridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yimi-go/logging"
+)
+
+// NameKey is the key of the attr carrying the Logger names.
+const NameKey = "logger"
+
+type factory struct {
+	handler slog.Handler
+}
+
+// NewFactory creates a Factory producing Loggers that log via handler,
+// so that libraries could use the facade API while applications choose slog handlers.
+//
+// The Loggers carry their names as attrs keyed by NameKey, unless the names are empty.
+// Fields are translated to attrs of the matching kinds, Namespaces to groups,
+// and Fields of LazyType added via WithField to slog.LogValuers, which are resolved when
+// the handlers see fit, e.g. the built-in handlers resolve them in WithAttrs.
+func NewFactory(handler slog.Handler) logging.Factory {
+	return &factory{handler: handler}
+}
+
+func (f *factory) Logger(name string) logging.Logger {
+	handler := f.handler
+	if name != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String(NameKey, name)})
+	}
+	return &slogLogger{handler: handler}
+}
+
+type slogLogger struct {
+	handler slog.Handler
+	skip    int
+}
+
+// SlogLevel maps a Level to the slog.Level of the same name.
+// OffLevel is mapped to a slog.Level above all the others.
+func SlogLevel(lvl logging.Level) slog.Level {
+	switch lvl {
+	case logging.DebugLevel:
+		return slog.LevelDebug
+	case logging.InfoLevel:
+		return slog.LevelInfo
+	case logging.WarnLevel:
+		return slog.LevelWarn
+	case logging.ErrorLevel:
+		return slog.LevelError
+	default:
+		return slog.Level(1<<31 - 1)
+	}
+}
+
+func (l *slogLogger) Enabled(lvl logging.Level) bool {
+	return lvl != logging.OffLevel && l.handler.Enabled(context.Background(), SlogLevel(lvl))
+}
+
+// log creates a record and handles it. It must be called by the logging methods directly.
+func (l *slogLogger) log(lvl logging.Level, message string, fields []logging.Field) {
+	var pcs [1]uintptr
+	// skip runtime.Callers, log and the logging method.
+	runtime.Callers(3+l.skip, pcs[:])
+	r := slog.NewRecord(time.Now(), SlogLevel(lvl), message, pcs[0])
+	r.AddAttrs(fieldAttrs(fields, false)...)
+	_ = l.handler.Handle(context.Background(), r)
+}
+
+func (l *slogLogger) Debug(v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.log(logging.DebugLevel, fmt.Sprint(v...), nil)
+	}
+}
+func (l *slogLogger) Debugln(v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.log(logging.DebugLevel, sprintln(v), nil)
+	}
+}
+func (l *slogLogger) Debugf(format string, v ...any) {
+	if l.Enabled(logging.DebugLevel) {
+		l.log(logging.DebugLevel, fmt.Sprintf(format, v...), nil)
+	}
+}
+func (l *slogLogger) Debugw(message string, field ...logging.Field) {
+	if l.Enabled(logging.DebugLevel) {
+		l.log(logging.DebugLevel, message, field)
+	}
+}
+func (l *slogLogger) Info(v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.log(logging.InfoLevel, fmt.Sprint(v...), nil)
+	}
+}
+func (l *slogLogger) Infoln(v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.log(logging.InfoLevel, sprintln(v), nil)
+	}
+}
+func (l *slogLogger) Infof(format string, v ...any) {
+	if l.Enabled(logging.InfoLevel) {
+		l.log(logging.InfoLevel, fmt.Sprintf(format, v...), nil)
+	}
+}
+func (l *slogLogger) Infow(message string, field ...logging.Field) {
+	if l.Enabled(logging.InfoLevel) {
+		l.log(logging.InfoLevel, message, field)
+	}
+}
+func (l *slogLogger) Warn(v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.log(logging.WarnLevel, fmt.Sprint(v...), nil)
+	}
+}
+func (l *slogLogger) Warnln(v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.log(logging.WarnLevel, sprintln(v), nil)
+	}
+}
+func (l *slogLogger) Warnf(format string, v ...any) {
+	if l.Enabled(logging.WarnLevel) {
+		l.log(logging.WarnLevel, fmt.Sprintf(format, v...), nil)
+	}
+}
+func (l *slogLogger) Warnw(message string, field ...logging.Field) {
+	if l.Enabled(logging.WarnLevel) {
+		l.log(logging.WarnLevel, message, field)
+	}
+}
+func (l *slogLogger) Error(v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.log(logging.ErrorLevel, fmt.Sprint(v...), nil)
+	}
+}
+func (l *slogLogger) Errorln(v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.log(logging.ErrorLevel, sprintln(v), nil)
+	}
+}
+func (l *slogLogger) Errorf(format string, v ...any) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.log(logging.ErrorLevel, fmt.Sprintf(format, v...), nil)
+	}
+}
+func (l *slogLogger) Errorw(message string, field ...logging.Field) {
+	if l.Enabled(logging.ErrorLevel) {
+		l.log(logging.ErrorLevel, message, field)
+	}
+}
+
+// WithField adds the attrs of field to the handler.
+// Namespaces are mapped to slog.Handler.WithGroup.
+func (l *slogLogger) WithField(field ...logging.Field) logging.Logger {
+	handler := l.handler
+	start := 0
+	for i, f := range field {
+		if f.Type() != logging.NamespaceType {
+			continue
+		}
+		if attrs := fieldAttrs(field[start:i], true); len(attrs) > 0 {
+			handler = handler.WithAttrs(attrs)
+		}
+		if f.Key() != "" {
+			handler = handler.WithGroup(f.Key())
+		}
+		start = i + 1
+	}
+	if attrs := fieldAttrs(field[start:], true); len(attrs) > 0 {
+		handler = handler.WithAttrs(attrs)
+	}
+	return &slogLogger{handler: handler, skip: l.skip}
+}
+func (l *slogLogger) WithGroup(name string) logging.Logger {
+	if name == "" {
+		return l
+	}
+	return &slogLogger{handler: l.handler.WithGroup(name), skip: l.skip}
+}
+func (l *slogLogger) WithCallerSkip(skip int) logging.Logger {
+	return &slogLogger{handler: l.handler, skip: l.skip + skip}
+}
+
+func sprintln(v []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
+
+// fieldAttrs translates fields to attrs. The Fields after a Namespace are nested into a group.
+// If lazy is true, Fields of LazyType are translated to slog.LogValuers rather than resolved.
+func fieldAttrs(fields []logging.Field, lazy bool) []slog.Attr {
+	if len(fields) == 0 {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i, f := range fields {
+		if f.Type() == logging.NamespaceType {
+			rest := fieldAttrs(fields[i+1:], lazy)
+			if f.Key() == "" {
+				return append(attrs, rest...)
+			}
+			return append(attrs, slog.Attr{Key: f.Key(), Value: slog.GroupValue(rest...)})
+		}
+		if lazy && f.Type() == logging.LazyType {
+			// an empty key with a group value is inlined when resolved.
+			attrs = append(attrs, slog.Any("", lazyValuer{f}))
+			continue
+		}
+		attrs = append(attrs, fieldAttr(f))
+	}
+	return attrs
+}
+
+// lazyValuer resolves a Field of LazyType when records are handled.
+type lazyValuer struct {
+	field logging.Field
+}
+
+func (v lazyValuer) LogValue() slog.Value {
+	return slog.GroupValue(fieldAttr(v.field))
+}
+
+// fieldAttr translates a Field to an attr of the matching kind.
+func fieldAttr(f logging.Field) slog.Attr {
+	var v attrVisitor
+	logging.Visit(f, &v)
+	return v.attr
+}
+
+// attrVisitor translates the visited Field to attr.
+type attrVisitor struct {
+	attr slog.Attr
+}
+
+func (v *attrVisitor) VisitUnknown(key string, value any)   { v.attr = slog.Any(key, value) }
+func (v *attrVisitor) VisitBinary(key string, value []byte) { v.attr = slog.Any(key, value) }
+func (v *attrVisitor) VisitBool(key string, value bool)     { v.attr = slog.Bool(key, value) }
+func (v *attrVisitor) VisitComplex128(key string, value complex128) {
+	v.attr = slog.String(key, fmt.Sprint(value))
+}
+func (v *attrVisitor) VisitComplex64(key string, value complex64) {
+	v.attr = slog.String(key, fmt.Sprint(value))
+}
+func (v *attrVisitor) VisitDuration(key string, value time.Duration) {
+	v.attr = slog.Duration(key, value)
+}
+func (v *attrVisitor) VisitFloat64(key string, value float64) { v.attr = slog.Float64(key, value) }
+func (v *attrVisitor) VisitFloat32(key string, value float32) {
+	v.attr = slog.Float64(key, float64(value))
+}
+func (v *attrVisitor) VisitInt64(key string, value int64)    { v.attr = slog.Int64(key, value) }
+func (v *attrVisitor) VisitInt32(key string, value int32)    { v.attr = slog.Int64(key, int64(value)) }
+func (v *attrVisitor) VisitInt16(key string, value int16)    { v.attr = slog.Int64(key, int64(value)) }
+func (v *attrVisitor) VisitInt8(key string, value int8)      { v.attr = slog.Int64(key, int64(value)) }
+func (v *attrVisitor) VisitString(key string, value string)  { v.attr = slog.String(key, value) }
+func (v *attrVisitor) VisitTime(key string, value time.Time) { v.attr = slog.Time(key, value) }
+func (v *attrVisitor) VisitUint64(key string, value uint64)  { v.attr = slog.Uint64(key, value) }
+func (v *attrVisitor) VisitUint32(key string, value uint32)  { v.attr = slog.Uint64(key, uint64(value)) }
+func (v *attrVisitor) VisitUint16(key string, value uint16)  { v.attr = slog.Uint64(key, uint64(value)) }
+func (v *attrVisitor) VisitUint8(key string, value uint8)    { v.attr = slog.Uint64(key, uint64(value)) }
+func (v *attrVisitor) VisitUintptr(key string, value uintptr) {
+	v.attr = slog.Uint64(key, uint64(value))
+}
+
+// VisitStringer formats value via fmt, which prints "<nil>" for typed nil pointers
+// rather than panicking as calling String directly does.
+func (v *attrVisitor) VisitStringer(key string, value fmt.Stringer) {
+	v.attr = slog.String(key, fmt.Sprint(value))
+}
+
+// VisitError expands the errors carrying Fields into groups of the message and the Fields,
+// as logging.ErrorFields expects. The other errors are left to the handler.
+func (v *attrVisitor) VisitError(key string, value error) {
+	carried := logging.ErrorFields(value)
+	if len(carried) == 0 {
+		v.attr = slog.Any(key, value)
+		return
+	}
+	v.attr = slog.Group(key,
+		slog.String("message", fmt.Sprint(value)),
+		slog.Attr{Key: "fields", Value: slog.GroupValue(fieldAttrs(carried, false)...)},
+	)
+}
+
+func (v *attrVisitor) VisitStack(key string, value *logging.Stacktrace) {
+	v.attr = slog.String(key, value.String())
+}
+func (v *attrVisitor) VisitGroup(key string, value []logging.Field) {
+	v.attr = slog.Attr{Key: key, Value: slog.GroupValue(fieldAttrs(value, false)...)}
+}
+func (v *attrVisitor) VisitNamespace(key string) {
+	v.attr = slog.Attr{Key: key, Value: slog.GroupValue()}
+}
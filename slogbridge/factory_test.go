@@ -0,0 +1,154 @@
+package slogbridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/errorx"
+)
+
+// tStringer panics if String is called on a nil pointer.
+type tStringer struct {
+	s string
+}
+
+func (t *tStringer) String() string { return t.s }
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m))
+		delete(m, slog.TimeKey)
+		records = append(records, m)
+	}
+	buf.Reset()
+	return records
+}
+
+func TestNewFactory(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true})
+	logger := NewFactory(handler).Logger("test")
+
+	logger.Debug("debug")
+	logger.Info("info ", 1)
+	logger.Infoln("info", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Error("error")
+	records := decode(t, &buf)
+	assert.Len(t, records, 4)
+	for _, r := range records {
+		assert.Equal(t, "test", r[NameKey])
+		assert.Equal(t, "github.com/yimi-go/logging/slogbridge.TestNewFactory", r[slog.SourceKey].(map[string]any)["function"])
+	}
+	assert.Equal(t, "info 1", records[0][slog.MessageKey])
+	assert.Equal(t, "INFO", records[0][slog.LevelKey])
+	assert.Equal(t, "info 2", records[1][slog.MessageKey])
+	assert.Equal(t, "WARN", records[2][slog.LevelKey])
+	assert.Equal(t, "ERROR", records[3][slog.LevelKey])
+
+	assert.False(t, logger.Enabled(logging.DebugLevel))
+	assert.True(t, logger.Enabled(logging.ErrorLevel))
+	assert.False(t, logger.Enabled(logging.OffLevel))
+}
+
+func TestNewFactory_fields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFactory(slog.NewJSONHandler(&buf, nil)).Logger("")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.Infow("fields",
+		logging.String("str", "v"),
+		logging.Int("int", 1),
+		logging.Int8("int8", -1),
+		logging.Uint32("uint32", 2),
+		logging.Uintptr("uintptr", 3),
+		logging.Float32("float32", 1.5),
+		logging.Bool("bool", true),
+		logging.Duration("dur", time.Second),
+		logging.Time("ts", now),
+		logging.Complex64("complex", 1+2i),
+		logging.Binary("bin", []byte("b")),
+		logging.Stringer("stringer", time.Second),
+		logging.NamedError("err", errors.New("e")),
+		logging.NamedError("errx", errorx.Wrap(errors.New("cause"), "failed", logging.Int("user", 1))),
+		logging.Stringer("nil_stringer", (*tStringer)(nil)),
+		logging.Any("any", []int{1}),
+		logging.Lazy("lazy", func() any { return 1 }),
+		logging.Group("g", logging.Int("a", 1), logging.Namespace("ns"), logging.Int("b", 2)),
+		logging.Group("", logging.Int("inline", 1)),
+		logging.Namespace("ns"),
+		logging.Int("nested", 1),
+	)
+	records := decode(t, &buf)
+	assert.Len(t, records, 1)
+	r := records[0]
+	assert.NotContains(t, r, NameKey)
+	assert.Equal(t, "v", r["str"])
+	assert.Equal(t, float64(1), r["int"])
+	assert.Equal(t, float64(-1), r["int8"])
+	assert.Equal(t, float64(2), r["uint32"])
+	assert.Equal(t, float64(3), r["uintptr"])
+	assert.Equal(t, 1.5, r["float32"])
+	assert.Equal(t, true, r["bool"])
+	assert.Equal(t, float64(time.Second), r["dur"])
+	assert.Equal(t, "2024-01-02T03:04:05Z", r["ts"])
+	assert.Equal(t, "(1+2i)", r["complex"])
+	assert.Equal(t, "Yg==", r["bin"])
+	assert.Equal(t, "1s", r["stringer"])
+	assert.Equal(t, "e", r["err"])
+	assert.Equal(t, map[string]any{
+		"message": "failed: cause",
+		"fields":  map[string]any{"user": float64(1)},
+	}, r["errx"])
+	assert.Equal(t, "<nil>", r["nil_stringer"])
+	assert.Equal(t, []any{float64(1)}, r["any"])
+	assert.Equal(t, float64(1), r["lazy"])
+	assert.Equal(t, map[string]any{"a": float64(1), "ns": map[string]any{"b": float64(2)}}, r["g"])
+	assert.Equal(t, float64(1), r["inline"])
+	assert.Equal(t, map[string]any{"nested": float64(1)}, r["ns"])
+}
+
+func TestNewFactory_withField(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFactory(slog.NewJSONHandler(&buf, nil)).Logger("test")
+	calls := 0
+	lazy := logging.LazyField(func() logging.Field {
+		calls++
+		return logging.Int("lazy", calls)
+	})
+	child := logger.WithField(logging.Int("a", 1), lazy, logging.Namespace("ns"), logging.Int("b", 2), logging.Namespace(""))
+	child = logging.WithGroup(child, "g")
+	child = logging.WithGroup(child, "")
+	logging.AddCallerSkip(child.WithField(), 1).Infow("m", logging.Int("c", 3))
+	child.Debugw("disabled")
+	records := decode(t, &buf)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []map[string]any{{
+		slog.LevelKey:   "INFO",
+		slog.MessageKey: "m",
+		NameKey:         "test",
+		"a":             float64(1),
+		"lazy":          float64(1),
+		"ns":            map[string]any{"b": float64(2), "g": map[string]any{"c": float64(3)}},
+	}}, records)
+}
+
+func TestNewFactory_roundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFactory(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})).Logger("rt")
+	NewLogger(logger).Debug("via slog", "n", 1)
+	assert.Contains(t, buf.String(), fmt.Sprintf("level=DEBUG msg=\"via slog\" %s=rt n=1", NameKey))
+}
@@ -0,0 +1,192 @@
+// Package slogbridge bridges log/slog and the logging facade in both directions:
+// Handler is a slog.Handler that logs via a Logger, so that dependencies logging through slog
+// join the same output, and NewFactory creates a Factory logging via a slog.Handler.
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+
+	"github.com/yimi-go/logging"
+)
+
+// maxSlogDepth limits the frames searched for the real callers.
+const maxSlogDepth = 32
+
+// Handler is a slog.Handler that logs records via a Logger.
+//
+// Levels are mapped to the nearest Level not greater than them, e.g. slog.LevelWarn-1 to InfoLevel.
+// Attrs are mapped to Fields of the matching FieldTypes, groups to Fields of GroupType,
+// and WithAttrs and WithGroup to WithField and logging.WithGroup.
+// The time of records is ignored, as vendors log their own.
+//
+// The callers of the slog methods are reported as callers, if the Logger implements
+// logging.CallerSkipper.
+type Handler struct {
+	logger logging.Logger
+}
+
+// NewHandler creates a Handler logging via logger.
+func NewHandler(logger logging.Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// NewLogger creates a slog.Logger logging via logger.
+func NewLogger(logger logging.Logger) *slog.Logger {
+	return slog.New(NewHandler(logger))
+}
+
+// Level maps a slog.Level to the nearest Level not greater than it.
+// Levels below slog.LevelInfo are mapped to DebugLevel.
+func Level(lvl slog.Level) logging.Level {
+	switch {
+	case lvl >= slog.LevelError:
+		return logging.ErrorLevel
+	case lvl >= slog.LevelWarn:
+		return logging.WarnLevel
+	case lvl >= slog.LevelInfo:
+		return logging.InfoLevel
+	default:
+		return logging.DebugLevel
+	}
+}
+
+// Enabled reports whether the Logger enables the Level mapped from lvl.
+func (h *Handler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.logger.Enabled(Level(lvl))
+}
+
+// Handle logs r via the Logger.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]logging.Field, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, attr)
+		return true
+	})
+	logger := h.logger
+	if _, ok := logger.(logging.CallerSkipper); ok {
+		logger = logging.AddCallerSkip(logger, callerDepth(r.PC))
+	}
+	switch Level(r.Level) {
+	case logging.DebugLevel:
+		logger.Debugw(r.Message, fields...)
+	case logging.InfoLevel:
+		logger.Infow(r.Message, fields...)
+	case logging.WarnLevel:
+		logger.Warnw(r.Message, fields...)
+	default:
+		logger.Errorw(r.Message, fields...)
+	}
+	return nil
+}
+
+// WithAttrs returns a Handler whose Logger has the Fields of attrs added via WithField.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]logging.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = appendAttr(fields, attr)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return &Handler{logger: h.logger.WithField(fields...)}
+}
+
+// WithGroup returns a Handler whose Logger nests the subsequent Fields under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{logger: logging.WithGroup(h.logger, name)}
+}
+
+// appendAttr appends the Fields of attr to fields, following the slog rules:
+// empty Attrs are ignored, Groups with empty keys are inlined, and empty Groups are ignored.
+func appendAttr(fields []logging.Field, attr slog.Attr) []logging.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Key == "" && attr.Value.Kind() == slog.KindAny && attr.Value.Any() == nil {
+		return fields
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(fields, attrField(attr))
+	}
+	attrs := attr.Value.Group()
+	if len(attrs) == 0 {
+		return fields
+	}
+	if attr.Key == "" {
+		for _, a := range attrs {
+			fields = appendAttr(fields, a)
+		}
+		return fields
+	}
+	children := make([]logging.Field, 0, len(attrs))
+	for _, a := range attrs {
+		children = appendAttr(children, a)
+	}
+	return append(fields, logging.Group(attr.Key, children...))
+}
+
+// attrField creates a Field of the FieldType matching the kind of a resolved non-group attr.
+func attrField(attr slog.Attr) logging.Field {
+	v := attr.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return logging.String(attr.Key, v.String())
+	case slog.KindInt64:
+		return logging.Int64(attr.Key, v.Int64())
+	case slog.KindUint64:
+		return logging.Uint64(attr.Key, v.Uint64())
+	case slog.KindFloat64:
+		return logging.Float64(attr.Key, v.Float64())
+	case slog.KindBool:
+		return logging.Bool(attr.Key, v.Bool())
+	case slog.KindDuration:
+		return logging.Duration(attr.Key, v.Duration())
+	case slog.KindTime:
+		return logging.Time(attr.Key, v.Time())
+	}
+	switch a := v.Any().(type) {
+	case error:
+		return logging.NamedError(attr.Key, a)
+	case []byte:
+		return logging.Binary(attr.Key, a)
+	default:
+		return logging.Any(attr.Key, a)
+	}
+}
+
+// callerDepth returns the number of frames between the caller of callerDepth's caller
+// and the function of pc, the caller recorded by slog.
+// If pc is not found in the stack, the frames of log/slog are counted instead.
+func callerDepth(pc uintptr) int {
+	var pcs [maxSlogDepth]uintptr
+	// skip runtime.Callers, callerDepth and its caller.
+	n := runtime.Callers(3, pcs[:])
+	function := ""
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		function = frame.Function
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	depth, slogDepth := 0, -1
+	for {
+		frame, more := frames.Next()
+		depth++
+		if function != "" && frame.Function == function {
+			return depth
+		}
+		if slogDepth < 0 && !strings.HasPrefix(frame.Function, "log/slog.") {
+			slogDepth = depth
+		}
+		if !more {
+			break
+		}
+	}
+	if slogDepth < 0 {
+		return depth
+	}
+	return slogDepth
+}
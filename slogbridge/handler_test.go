@@ -0,0 +1,101 @@
+package slogbridge
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yimi-go/logging"
+	"github.com/yimi-go/logging/internal/logtest"
+)
+
+type tValuer struct{}
+
+func (tValuer) LogValue() slog.Value { return slog.StringValue("resolved") }
+
+func TestHandler(t *testing.T) {
+	rec := logtest.New(logging.InfoLevel)
+	logger := NewLogger(rec)
+	now := time.Now()
+	err := errors.New("e")
+
+	logger.Debug("debug")
+	logger.Info("info",
+		"str", "v",
+		"int", 1,
+		slog.Uint64("uint", 2),
+		slog.Float64("float", 1.5),
+		slog.Bool("bool", true),
+		slog.Duration("dur", time.Second),
+		slog.Time("time", now),
+		slog.Any("err", err),
+		slog.Any("bytes", []byte("b")),
+		slog.Any("slice", []int{1}),
+		slog.Any("valuer", tValuer{}),
+		slog.Group("g", slog.Int("a", 1), slog.Group("empty")),
+		slog.Group("", slog.Int("inline", 1)),
+		slog.Attr{},
+	)
+	logger.With("k", "v", slog.Group("empty")).WithGroup("req").WithGroup("").Warn("warn", "id", 1)
+	logger.Log(context.Background(), slog.LevelError+4, "error")
+	slog.New(NewHandler(rec).WithAttrs(nil)).Log(context.Background(), slog.LevelWarn-1, "info")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 4)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging/slogbridge.TestHandler", e.Caller.Function)
+	}
+	assert.Equal(t, logging.InfoLevel, entries[0].Level)
+	assert.Equal(t, "info", entries[0].Message)
+	assert.Equal(t, []logging.Field{
+		logging.String("str", "v"),
+		logging.Int64("int", 1),
+		logging.Uint64("uint", 2),
+		logging.Float64("float", 1.5),
+		logging.Bool("bool", true),
+		logging.Duration("dur", time.Second),
+		logging.Time("time", now),
+		logging.NamedError("err", err),
+		logging.Binary("bytes", []byte("b")),
+		logging.Any("slice", []int{1}),
+		logging.String("valuer", "resolved"),
+		logging.Group("g", logging.Int64("a", 1)),
+		logging.Int64("inline", 1),
+	}, entries[0].Fields)
+	assert.Equal(t, logging.WarnLevel, entries[1].Level)
+	assert.Equal(t, []logging.Field{
+		logging.String("k", "v"),
+		logging.Namespace("req"),
+		logging.Int64("id", 1),
+	}, entries[1].Fields)
+	assert.Equal(t, logging.ErrorLevel, entries[2].Level)
+	assert.Equal(t, logging.InfoLevel, entries[3].Level)
+}
+
+func TestHandler_debug(t *testing.T) {
+	rec := logtest.New(logging.DebugLevel)
+	h := NewHandler(rec)
+	assert.True(t, h.Enabled(context.Background(), slog.LevelDebug-4))
+	assert.Same(t, h, h.WithGroup(""))
+	slog.New(h).Debug("debug")
+	// records built by hand have no PC, the frames of log/slog are skipped.
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "manual", 0)
+	_ = h.Handle(context.Background(), r)
+	entries := rec.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, logging.DebugLevel, entries[0].Level)
+	assert.Equal(t, "github.com/yimi-go/logging/slogbridge.TestHandler_debug", entries[0].Caller.Function)
+	assert.Equal(t, "github.com/yimi-go/logging/slogbridge.TestHandler_debug", entries[1].Caller.Function)
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, logging.DebugLevel, Level(slog.LevelDebug))
+	assert.Equal(t, logging.DebugLevel, Level(slog.LevelInfo-1))
+	assert.Equal(t, logging.InfoLevel, Level(slog.LevelInfo))
+	assert.Equal(t, logging.WarnLevel, Level(slog.LevelWarn+1))
+	assert.Equal(t, logging.ErrorLevel, Level(slog.LevelError+8))
+}
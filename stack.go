@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackFormat is a format of Stacktrace text.
+type StackFormat uint8
+
+const (
+	// StackFull formats every frame in 2 lines, the function and the full file path with line,
+	// like the Go runtime does when panicking.
+	StackFull StackFormat = iota
+	// StackCompact formats every frame in 1 line, the function and the short file path with line.
+	StackCompact
+	// StackFuncLine formats all frames in 1 line, in form of "function:line",
+	// from the innermost to the outermost, separated by " <- ".
+	StackFuncLine
+)
+
+// Stacktrace is a stacktrace captured by CaptureStack.
+type Stacktrace struct {
+	pcs []uintptr
+}
+
+// CaptureStack captures the stacktrace of the current goroutine.
+// Skip is the number of frames to skip, 0 means the stacktrace starts from
+// the caller of CaptureStack.
+func CaptureStack(skip int) *Stacktrace {
+	if skip < 0 {
+		skip = 0
+	}
+	return captureStack(skip + 1)
+}
+
+func captureStack(skip int) *Stacktrace {
+	// skip runtime.Callers and captureStack
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+2, pcs)
+	for n == len(pcs) {
+		pcs = make([]uintptr, len(pcs)*2)
+		n = runtime.Callers(skip+2, pcs)
+	}
+	return &Stacktrace{pcs: pcs[:n]}
+}
+
+// Callers returns the program counters of the stacktrace, as runtime.Callers fills.
+func (s *Stacktrace) Callers() []uintptr {
+	if s == nil {
+		return nil
+	}
+	return s.pcs
+}
+
+// Frames returns the frames of the stacktrace, from the innermost to the outermost.
+// Frames of the Go runtime and the testing package are trimmed.
+func (s *Stacktrace) Frames() []runtime.Frame {
+	if s == nil || len(s.pcs) == 0 {
+		return nil
+	}
+	var list []runtime.Frame
+	frames := runtime.CallersFrames(s.pcs)
+	for {
+		frame, more := frames.Next()
+		if !trimFrame(frame) {
+			list = append(list, frame)
+		}
+		if !more {
+			break
+		}
+	}
+	return list
+}
+
+func trimFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, "runtime.") || strings.HasPrefix(frame.Function, "testing.")
+}
+
+// Format returns the stacktrace text in the given format.
+func (s *Stacktrace) Format(format StackFormat) string {
+	var b strings.Builder
+	for i, frame := range s.Frames() {
+		switch format {
+		case StackCompact:
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(frame.Function)
+			b.WriteString(" (")
+			b.WriteString(shortPath(frame.File))
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+			b.WriteByte(')')
+		case StackFuncLine:
+			if i > 0 {
+				b.WriteString(" <- ")
+			}
+			b.WriteString(frame.Function)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+		default:
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(frame.Function)
+			b.WriteString("\n\t")
+			b.WriteString(frame.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+		}
+	}
+	return b.String()
+}
+
+// String returns the stacktrace text in StackFull format.
+func (s *Stacktrace) String() string {
+	return s.Format(StackFull)
+}
+
+// shortPath returns the last directory and the file name of path.
+func shortPath(path string) string {
+	i := strings.LastIndexByte(path, '/')
+	if i <= 0 {
+		return path
+	}
+	if j := strings.LastIndexByte(path[:i], '/'); j >= 0 {
+		return path[j+1:]
+	}
+	return path
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureHelper() *Stacktrace {
+	return CaptureStack(0)
+}
+
+func TestCaptureStack(t *testing.T) {
+	s := captureHelper()
+	frames := s.Frames()
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "github.com/yimi-go/logging.captureHelper", frames[0].Function)
+	assert.Equal(t, "github.com/yimi-go/logging.TestCaptureStack", frames[1].Function)
+	assert.NotEmpty(t, s.Callers())
+
+	s = CaptureStack(-1)
+	assert.Equal(t, "github.com/yimi-go/logging.TestCaptureStack", s.Frames()[0].Function)
+}
+
+func deepStack(n int) *Stacktrace {
+	if n == 0 {
+		return CaptureStack(0)
+	}
+	return deepStack(n - 1)
+}
+
+func TestCaptureStack_deep(t *testing.T) {
+	s := deepStack(100)
+	assert.Len(t, s.Frames(), 102)
+}
+
+func TestStacktrace_nil(t *testing.T) {
+	var s *Stacktrace
+	assert.Nil(t, s.Callers())
+	assert.Nil(t, s.Frames())
+	assert.Equal(t, "", s.String())
+}
+
+func TestStacktrace_Format(t *testing.T) {
+	s := captureHelper()
+	full := s.Format(StackFull)
+	assert.Regexp(t, regexp.MustCompile(
+		`^github.com/yimi-go/logging.captureHelper\n\t/.+/stack_test.go:\d+\n`+
+			`github.com/yimi-go/logging.TestStacktrace_Format\n\t/.+/stack_test.go:\d+$`), full)
+	assert.Equal(t, full, s.String())
+	assert.Regexp(t, regexp.MustCompile(
+		`^github.com/yimi-go/logging.captureHelper \([^/]+/stack_test.go:\d+\)\n`+
+			`github.com/yimi-go/logging.TestStacktrace_Format \([^/]+/stack_test.go:\d+\)$`),
+		s.Format(StackCompact))
+	funcLine := s.Format(StackFuncLine)
+	assert.Regexp(t, regexp.MustCompile(
+		`^github.com/yimi-go/logging.captureHelper:\d+ <- github.com/yimi-go/logging.TestStacktrace_Format:\d+$`),
+		funcLine)
+	assert.False(t, strings.Contains(funcLine, "testing."))
+}
+
+func Test_shortPath(t *testing.T) {
+	assert.Equal(t, "logging/stack.go", shortPath("/root/logging/stack.go"))
+	assert.Equal(t, "/stack.go", shortPath("/stack.go"))
+	assert.Equal(t, "stack.go", shortPath("stack.go"))
+	assert.Equal(t, "a/stack.go", shortPath("a/stack.go"))
+}
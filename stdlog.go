@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The keys of the Fields parsed from the prefixes and headers of the standard library logs.
+const (
+	// StdLogPrefixKey is the key of the prefix of the standard library Logger, spaces trimmed.
+	StdLogPrefixKey = "log.prefix"
+	// StdLogTimeKey is the key of the time in the header, if log.Ldate or log.Ltime is set.
+	StdLogTimeKey = "log.time"
+	// StdLogFileKey is the key of the file name in the header, if log.Lshortfile or log.Llongfile is set.
+	StdLogFileKey = "log.origin.file.name"
+	// StdLogLineKey is the key of the line number in the header, if log.Lshortfile or log.Llongfile is set.
+	StdLogLineKey = "log.origin.file.line"
+)
+
+// maxStdLogDepth limits the frames of the log packages searched for the real callers.
+const maxStdLogDepth = 16
+
+// stdWriter is the output of standard library Loggers, which parses the outputs and logs them
+// via logger at level.
+//
+// The prefix and flags of the standard library Logger are snapshotted when the stdWriter is built,
+// as calling back into the Logger from Write deadlocks on the Go versions before 1.21.
+type stdWriter struct {
+	logger Logger
+	prefix string
+	flags  int
+	level  Level
+}
+
+// NewStdLog returns a standard library Logger, whose outputs are logged via logger at level,
+// e.g. for http.Server.ErrorLog.
+//
+// The returned Logger has neither prefix nor flags; the ones set later via SetPrefix and SetFlags
+// are not parsed, but kept in the messages.
+// The callers of the standard library Logger are reported as callers, if logger implements CallerSkipper.
+func NewStdLog(logger Logger, level Level) *log.Logger {
+	return log.New(&stdWriter{logger: logger, level: level}, "", 0)
+}
+
+// RedirectStdLog redirects the outputs of the standard library default Logger,
+// i.e. log.Print and so on, to logger at InfoLevel, as NewStdLog does.
+// The prefix and flags of the default Logger when RedirectStdLog is called are kept and parsed
+// into Fields, see StdLogPrefixKey and so on; the ones set later are not parsed.
+//
+// It returns a function restoring the output of the default Logger.
+func RedirectStdLog(logger Logger) func() {
+	std := log.Default()
+	origin := std.Writer()
+	std.SetOutput(&stdWriter{logger: logger, prefix: std.Prefix(), flags: std.Flags(), level: InfoLevel})
+	return func() {
+		std.SetOutput(origin)
+	}
+}
+
+// Write parses p, a line formatted by the standard library Logger, and logs it.
+// It never fails.
+func (w *stdWriter) Write(p []byte) (int, error) {
+	if !w.logger.Enabled(w.level) {
+		return len(p), nil
+	}
+	message, fields := parseStdLog(string(p), w.prefix, w.flags)
+	// skip the frames of the log packages, and Write itself.
+	logger := AddCallerSkip(w.logger, stdLogDepth()+1)
+	switch w.level {
+	case DebugLevel:
+		logger.Debugw(message, fields...)
+	case InfoLevel:
+		logger.Infow(message, fields...)
+	case WarnLevel:
+		logger.Warnw(message, fields...)
+	case ErrorLevel:
+		logger.Errorw(message, fields...)
+	}
+	return len(p), nil
+}
+
+// stdLogDepth returns the number of frames of the log packages calling the caller of stdLogDepth.
+func stdLogDepth() int {
+	var pcs [maxStdLogDepth]uintptr
+	// skip runtime.Callers, stdLogDepth and its caller.
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") && !strings.HasPrefix(frame.Function, "log/slog.") {
+			return depth
+		}
+		depth++
+		if !more {
+			return depth
+		}
+	}
+}
+
+// parseStdLog parses a line formatted by the standard library Logger with prefix and flags
+// into the message and Fields. The unparsable parts are kept in the message.
+func parseStdLog(line, prefix string, flags int) (string, []Field) {
+	line = strings.TrimSuffix(line, "\n")
+	var fields []Field
+	if trimmed := strings.TrimSpace(prefix); trimmed != "" {
+		fields = append(fields, String(StdLogPrefixKey, trimmed))
+	}
+	if prefix != "" && flags&log.Lmsgprefix == 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		var t time.Time
+		var ok bool
+		if t, line, ok = parseStdLogTime(line, flags); ok {
+			fields = append(fields, Time(StdLogTimeKey, t))
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if end := strings.Index(line, ": "); end > 0 {
+			if colon := strings.LastIndexByte(line[:end], ':'); colon > 0 {
+				if n, err := strconv.Atoi(line[colon+1 : end]); err == nil {
+					fields = append(fields, String(StdLogFileKey, line[:colon]), Int(StdLogLineKey, n))
+					line = line[end+2:]
+				}
+			}
+		}
+	}
+	if prefix != "" && flags&log.Lmsgprefix != 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+	return line, fields
+}
+
+// parseStdLogTime parses the date and time header formatted by the standard library Logger,
+// and returns the rest of line.
+func parseStdLogTime(line string, flags int) (time.Time, string, bool) {
+	var layout string
+	if flags&log.Ldate != 0 {
+		layout = "2006/01/02 "
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		layout += "15:04:05"
+		if flags&log.Lmicroseconds != 0 {
+			layout += ".000000"
+		}
+		layout += " "
+	}
+	if len(line) < len(layout) {
+		return time.Time{}, line, false
+	}
+	loc := time.Local
+	if flags&log.LUTC != 0 {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(layout, line[:len(layout)], loc)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	if flags&log.Ldate == 0 {
+		// only the clock is logged, take today's date.
+		now := time.Now().In(loc)
+		t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+	return t, line[len(layout):], true
+}
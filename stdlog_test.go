@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStdLog(t *testing.T) {
+	var entries []tEntry
+	std := NewStdLog(&tel{entries: &entries, lvl: InfoLevel}, WarnLevel)
+	std.Printf("hello %d", 1)
+	std.Println("world")
+	std.SetPrefix("[http] ")
+	std.SetFlags(log.Lshortfile)
+	std.Print("with prefix")
+
+	assert.Len(t, entries, 3)
+	for _, e := range entries {
+		assert.Equal(t, "github.com/yimi-go/logging.TestNewStdLog", e.caller.Function)
+		assert.Equal(t, WarnLevel, e.lvl)
+	}
+	assert.Equal(t, "hello 1", entries[0].msg)
+	assert.Empty(t, entries[0].fields)
+	assert.Equal(t, "world", entries[1].msg)
+	// the prefix and flags set later are not parsed.
+	assert.Equal(t, fmt.Sprintf("[http] stdlog_test.go:%d: with prefix", entries[2].caller.Line), entries[2].msg)
+	assert.Empty(t, entries[2].fields)
+
+	std = NewStdLog(&tel{entries: &entries, lvl: InfoLevel}, DebugLevel)
+	std.Print("disabled")
+	assert.Len(t, entries, 3)
+}
+
+func TestNewStdLog_levels(t *testing.T) {
+	for _, lvl := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel} {
+		var entries []tEntry
+		NewStdLog(&tel{entries: &entries, lvl: DebugLevel}, lvl).Print("m")
+		assert.Equal(t, []tEntry{{caller: entries[0].caller, msg: "m", lvl: lvl}}, entries)
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	flags := log.Flags()
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(flags)
+		log.SetPrefix("")
+	}()
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds | log.LUTC | log.Lmsgprefix)
+	log.SetPrefix("app: ")
+
+	var entries []tEntry
+	restore := RedirectStdLog(&tel{entries: &entries, lvl: InfoLevel})
+	before := time.Now().Truncate(time.Microsecond)
+	log.Print("redirected")
+	restore()
+	log.Print("restored")
+
+	assert.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, "github.com/yimi-go/logging.TestRedirectStdLog", e.caller.Function)
+	assert.Equal(t, InfoLevel, e.lvl)
+	assert.Equal(t, "redirected", e.msg)
+	assert.Len(t, e.fields, 2)
+	assert.Equal(t, String(StdLogPrefixKey, "app:"), e.fields[0])
+	assert.Equal(t, StdLogTimeKey, e.fields[1].Key())
+	assert.WithinDuration(t, before, AttrOf(e.fields[1]).TimeValue(), time.Second)
+	assert.Contains(t, buf.String(), "app: restored")
+}
+
+func TestParseStdLog(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	msg, fields := parseStdLog("2024/05/06 07:08:09 /a/b.go:12: hi\n", "", log.LstdFlags|log.LUTC|log.Llongfile)
+	assert.Equal(t, "hi", msg)
+	assert.Equal(t, []Field{Time(StdLogTimeKey, now), String(StdLogFileKey, "/a/b.go"), Int(StdLogLineKey, 12)}, fields)
+
+	msg, fields = parseStdLog("p 07:08:09 msg", "p ", log.Ltime|log.LUTC)
+	assert.Equal(t, "msg", msg)
+	assert.Equal(t, "p", AttrOf(fields[0]).StringValue())
+	assert.Equal(t, 7, AttrOf(fields[1]).TimeValue().Hour())
+	assert.Equal(t, time.Now().UTC().Day(), AttrOf(fields[1]).TimeValue().Day())
+
+	msg, fields = parseStdLog("garbage", "", log.LstdFlags|log.Lshortfile)
+	assert.Equal(t, "garbage", msg)
+	assert.Empty(t, fields)
+	msg, _ = parseStdLog("2024/13/06 07:08:09 x", "", log.LstdFlags)
+	assert.Equal(t, "2024/13/06 07:08:09 x", msg)
+	msg, fields = parseStdLog("a.go:x: m", "", log.Lshortfile)
+	assert.Equal(t, "a.go:x: m", msg)
+	assert.Empty(t, fields)
+}
@@ -0,0 +1,233 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedactedValue is the value that replaces the value of redacted struct fields.
+const RedactedValue = "[REDACTED]"
+
+// maxStructDepth limits how deep Struct walks into nested structs,
+// which also protects Struct from cyclic pointers.
+const maxStructDepth = 16
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// Struct creates a Field of GroupType value by walking the exported fields of v,
+// which should be a struct or a pointer to struct.
+// If v is not, a Field inferred from the dynamic type of v is returned as Lazy does.
+//
+// The child Fields are created by the constructors matching the types of the struct fields,
+// for example, a time.Time struct field results a Field of TimeType.
+// Nested structs result nested Fields of GroupType.
+// Slices, maps and other values that have no matching constructor result Fields of UnknownType.
+// Non-nil pointers are dereferenced first if the pointed values have matching constructors
+// or registered encoders, so a *time.Time results a Field of TimeType rather than StringerType.
+//
+// The struct fields could be customized via "log" tags, in format of `log:"name,opt1,opt2"`.
+// An empty name means using the Go field name, and the name "-" means skip the field.
+// The supported options are:
+//   - omitempty: skip the field if it has a zero value.
+//   - redact: replace the field value with RedactedValue.
+//   - inline: put the children of the field into the parent Group instead of nesting them.
+//
+// Embedded structs without tag names are inlined, as encoding/json does.
+func Struct(key string, v any) Field {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return inferField(key, v)
+	}
+	if isLeafStruct(rv.Type()) {
+		return structValue(key, reflect.ValueOf(v), 0)
+	}
+	return Group(key, structFields(rv, 0)...)
+}
+
+type structField struct {
+	name      string
+	index     int
+	omitempty bool
+	redact    bool
+	inline    bool
+}
+
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+func cachedStructFields(t reflect.Type) []structField {
+	if sfs, ok := structFieldCache.Load(t); ok {
+		return sfs.([]structField)
+	}
+	sfs := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag, hasTag := f.Tag.Lookup("log")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		sf := structField{name: name, index: i}
+		for opts != "" {
+			var opt string
+			opt, opts, _ = strings.Cut(opts, ",")
+			switch opt {
+			case "omitempty":
+				sf.omitempty = true
+			case "redact":
+				sf.redact = true
+			case "inline":
+				sf.inline = true
+			}
+		}
+		if f.Anonymous && (!hasTag || name == "") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isLeafStruct(ft) {
+				sf.inline = true
+			} else if !f.IsExported() {
+				continue
+			}
+		}
+		if sf.name == "" {
+			sf.name = f.Name
+		}
+		sfs = append(sfs, sf)
+	}
+	actual, _ := structFieldCache.LoadOrStore(t, sfs)
+	return actual.([]structField)
+}
+
+func structFields(rv reflect.Value, depth int) []Field {
+	sfs := cachedStructFields(rv.Type())
+	fields := make([]Field, 0, len(sfs))
+	for _, sf := range sfs {
+		fv := rv.Field(sf.index)
+		if sf.omitempty && fv.IsZero() {
+			continue
+		}
+		if sf.redact {
+			fields = append(fields, String(sf.name, RedactedValue))
+			continue
+		}
+		if sf.inline {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && !isLeafStruct(fv.Type()) {
+				if depth < maxStructDepth {
+					fields = append(fields, structFields(fv, depth+1)...)
+				}
+				continue
+			}
+			if fv.Kind() == reflect.Pointer {
+				// nil pointer to struct, nothing to inline.
+				continue
+			}
+		}
+		fields = append(fields, structValue(sf.name, fv, depth))
+	}
+	return fields
+}
+
+// isLeafStruct reports whether values of struct type t should be output as a whole
+// rather than walked into.
+func isLeafStruct(t reflect.Type) bool {
+	return t == timeType || hasEncoder(t) ||
+		t.Implements(errorType) || t.Implements(stringerType) ||
+		reflect.PointerTo(t).Implements(errorType) || reflect.PointerTo(t).Implements(stringerType)
+}
+
+func structValue(name string, fv reflect.Value, depth int) Field {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return Any(name, nil)
+		}
+		if fv.Kind() == reflect.Pointer {
+			if hasEncoder(fv.Type()) {
+				return inferField(name, fv.Interface())
+			}
+			// Pointers to values with their own constructors or encoders are dereferenced,
+			// even if the pointers implement error or fmt.Stringer, as *time.Time does.
+			if et := fv.Type().Elem(); et != timeType && et != durationType && !hasEncoder(et) &&
+				(fv.Type().Implements(errorType) || fv.Type().Implements(stringerType)) {
+				return inferField(name, fv.Interface())
+			}
+		}
+		fv = fv.Elem()
+	}
+	t := fv.Type()
+	switch {
+	case t == timeType:
+		return Time(name, fv.Interface().(time.Time))
+	case t == durationType:
+		return Duration(name, time.Duration(fv.Int()))
+	case hasEncoder(t), t.Implements(errorType), t.Implements(stringerType):
+		return inferField(name, fv.Interface())
+	case reflect.PointerTo(t).Implements(errorType), reflect.PointerTo(t).Implements(stringerType):
+		ptr := reflect.New(t)
+		ptr.Elem().Set(fv)
+		return inferField(name, ptr.Interface())
+	}
+	switch fv.Kind() {
+	case reflect.Bool:
+		return Bool(name, fv.Bool())
+	case reflect.Int:
+		return Int(name, int(fv.Int()))
+	case reflect.Int64:
+		return Int64(name, fv.Int())
+	case reflect.Int32:
+		return Int32(name, int32(fv.Int()))
+	case reflect.Int16:
+		return Int16(name, int16(fv.Int()))
+	case reflect.Int8:
+		return Int8(name, int8(fv.Int()))
+	case reflect.Uint:
+		return Uint(name, uint(fv.Uint()))
+	case reflect.Uint64:
+		return Uint64(name, fv.Uint())
+	case reflect.Uint32:
+		return Uint32(name, uint32(fv.Uint()))
+	case reflect.Uint16:
+		return Uint16(name, uint16(fv.Uint()))
+	case reflect.Uint8:
+		return Uint8(name, uint8(fv.Uint()))
+	case reflect.Uintptr:
+		return Uintptr(name, uintptr(fv.Uint()))
+	case reflect.Float64:
+		return Float64(name, fv.Float())
+	case reflect.Float32:
+		return Float32(name, float32(fv.Float()))
+	case reflect.Complex128:
+		return Complex128(name, fv.Complex())
+	case reflect.Complex64:
+		return Complex64(name, complex64(fv.Complex()))
+	case reflect.String:
+		return String(name, fv.String())
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			return Binary(name, fv.Bytes())
+		}
+	case reflect.Struct:
+		if depth >= maxStructDepth {
+			return Any(name, fv.Interface())
+		}
+		return Group(name, structFields(fv, depth+1)...)
+	}
+	return Any(name, fv.Interface())
+}
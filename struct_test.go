@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tAddress struct {
+	City string `log:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+type tAudit struct {
+	CreatedBy string `log:"created_by"`
+}
+
+type tNode struct {
+	Next *tNode `log:"next"`
+	Name string `log:"name"`
+}
+
+type tUser struct {
+	Birthday time.Time `log:"birthday"`
+	tAudit
+	Err      error           `log:"err"`
+	Home     *tAddress       `log:"home"`
+	Work     *tAddress       `log:"work"`
+	Extra    *tAddress       `log:",inline"`
+	Tags     []string        `log:"tags"`
+	Avatar   []byte          `log:"avatar"`
+	Password string          `log:"password,redact"`
+	Name     string          `log:"name"`
+	Nick     string          `log:"nick,omitempty"`
+	Ignored  string          `log:"-"`
+	Flag     hanBoolStringer `log:"flag"`
+	secret   string
+	ID       int64 `log:"id"`
+	Timeout  time.Duration
+	Age      uint8 `log:"age"`
+	Admin    bool  `log:"admin"`
+}
+
+func TestStruct(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := tUser{
+		tAudit:   tAudit{CreatedBy: "admin"},
+		ID:       1,
+		Name:     "foo",
+		Password: "123456",
+		Birthday: birthday,
+		Timeout:  time.Second,
+		Age:      18,
+		Home:     &tAddress{City: "Shanghai"},
+		Extra:    &tAddress{City: "Beijing", Zip: "100000"},
+		Tags:     []string{"a"},
+		Avatar:   []byte("png"),
+		Flag:     true,
+		Err:      io.EOF,
+		Ignored:  "ignored",
+		secret:   "secret",
+	}
+	want := Group("user",
+		Time("birthday", birthday),
+		String("created_by", "admin"),
+		NamedError("err", io.EOF),
+		Group("home", String("city", "Shanghai")),
+		Any("work", nil),
+		String("city", "Beijing"),
+		String("zip", "100000"),
+		Any("tags", []string{"a"}),
+		Binary("avatar", []byte("png")),
+		String("password", RedactedValue),
+		String("name", "foo"),
+		Stringer("flag", hanBoolStringer(true)),
+		Int64("id", 1),
+		Duration("Timeout", time.Second),
+		Uint8("age", 18),
+		Bool("admin", false),
+	)
+	assert.Equal(t, want, Struct("user", u))
+	assert.Equal(t, want, Struct("user", &u))
+}
+
+func TestStruct_notStruct(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, Time("key", now), Struct("key", now))
+	assert.Equal(t, String("key", "v"), Struct("key", "v"))
+	assert.Equal(t, Any("key", nil), Struct("key", nil))
+	assert.Equal(t, Any("key", (*tAddress)(nil)), Struct("key", (*tAddress)(nil)))
+	assert.Equal(t, Time("key", now), Struct("key", &now))
+}
+
+func TestStruct_pointers(t *testing.T) {
+	type event struct {
+		At      *time.Time     `log:"at"`
+		Timeout *time.Duration `log:"timeout"`
+		Price   *tMoney        `log:"price"`
+		Flag    *hanBoolStringer
+		Missing *time.Time `log:"missing"`
+	}
+	RegisterEncoder(func(key string, value tMoney) Field { return Int(key, value.Units) })
+	defer RegisterEncoder[tMoney](nil)
+	at := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeout := time.Second
+	flag := hanBoolStringer(true)
+	assert.Equal(t, Group("e",
+		Time("at", at),
+		Duration("timeout", time.Second),
+		Int("price", 3),
+		Stringer("Flag", &flag),
+		Any("missing", nil),
+	), Struct("e", event{At: &at, Timeout: &timeout, Price: &tMoney{Units: 3}, Flag: &flag}))
+}
+
+func TestStruct_cyclic(t *testing.T) {
+	n := &tNode{Name: "a"}
+	n.Next = n
+	f := Struct("node", n)
+	depth := 0
+	for f.Type() == GroupType {
+		depth++
+		f = f.Value().([]Field)[0]
+	}
+	assert.Equal(t, maxStructDepth+1, depth)
+	assert.Equal(t, UnknownType, f.Type())
+}
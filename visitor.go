@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// FieldVisitor visits Fields by their FieldTypes, with typed values.
+//
+// A method is added to FieldVisitor whenever a FieldType is added, so that vendors
+// implementing it get compile errors rather than silently mishandling the new FieldType.
+type FieldVisitor interface {
+	// VisitUnknown visits a Field of UnknownType.
+	VisitUnknown(key string, value any)
+	// VisitBinary visits a Field of BinaryType.
+	VisitBinary(key string, value []byte)
+	// VisitBool visits a Field of BoolType.
+	VisitBool(key string, value bool)
+	// VisitComplex128 visits a Field of Complex128Type.
+	VisitComplex128(key string, value complex128)
+	// VisitComplex64 visits a Field of Complex64Type.
+	VisitComplex64(key string, value complex64)
+	// VisitDuration visits a Field of DurationType.
+	VisitDuration(key string, value time.Duration)
+	// VisitFloat64 visits a Field of Float64Type.
+	VisitFloat64(key string, value float64)
+	// VisitFloat32 visits a Field of Float32Type.
+	VisitFloat32(key string, value float32)
+	// VisitInt64 visits a Field of Int64Type.
+	VisitInt64(key string, value int64)
+	// VisitInt32 visits a Field of Int32Type.
+	VisitInt32(key string, value int32)
+	// VisitInt16 visits a Field of Int16Type.
+	VisitInt16(key string, value int16)
+	// VisitInt8 visits a Field of Int8Type.
+	VisitInt8(key string, value int8)
+	// VisitString visits a Field of StringType.
+	VisitString(key string, value string)
+	// VisitTime visits a Field of TimeType.
+	VisitTime(key string, value time.Time)
+	// VisitUint64 visits a Field of Uint64Type.
+	VisitUint64(key string, value uint64)
+	// VisitUint32 visits a Field of Uint32Type.
+	VisitUint32(key string, value uint32)
+	// VisitUint16 visits a Field of Uint16Type.
+	VisitUint16(key string, value uint16)
+	// VisitUint8 visits a Field of Uint8Type.
+	VisitUint8(key string, value uint8)
+	// VisitUintptr visits a Field of UintptrType.
+	VisitUintptr(key string, value uintptr)
+	// VisitStringer visits a Field of StringerType. The value is never nil.
+	VisitStringer(key string, value fmt.Stringer)
+	// VisitError visits a Field of ErrorType. The value is never nil.
+	VisitError(key string, value error)
+	// VisitStack visits a Field of StackType.
+	VisitStack(key string, value *Stacktrace)
+	// VisitGroup visits a Field of GroupType.
+	// The visitor is expected to visit the children via Visit.
+	VisitGroup(key string, value []Field)
+	// VisitNamespace visits a Field of NamespaceType.
+	VisitNamespace(key string)
+}
+
+// Visit calls the method of visitor matching the FieldType of f, with the typed value.
+//
+// Fields of LazyType are resolved first, and Fields of UnknownType are encoded by the
+// encoders registered via RegisterEncoder. Fields carrying nil fmt.Stringer or error,
+// and Fields of unknown FieldTypes, are visited via VisitUnknown.
+func Visit(field Field, visitor FieldVisitor) {
+	f := Encode(Resolve(field))
+	switch f.typ {
+	case BinaryType:
+		v, _ := f.iface.([]byte)
+		visitor.VisitBinary(f.key, v)
+	case BoolType:
+		visitor.VisitBool(f.key, f.BoolValue())
+	case Complex128Type:
+		v, _ := f.iface.(complex128)
+		visitor.VisitComplex128(f.key, v)
+	case Complex64Type:
+		v, _ := f.iface.(complex64)
+		visitor.VisitComplex64(f.key, v)
+	case DurationType:
+		visitor.VisitDuration(f.key, f.DurationValue())
+	case Float64Type:
+		visitor.VisitFloat64(f.key, f.Float64Value())
+	case Float32Type:
+		visitor.VisitFloat32(f.key, float32(f.Float64Value()))
+	case Int64Type:
+		visitor.VisitInt64(f.key, f.num)
+	case Int32Type:
+		visitor.VisitInt32(f.key, int32(f.num))
+	case Int16Type:
+		visitor.VisitInt16(f.key, int16(f.num))
+	case Int8Type:
+		visitor.VisitInt8(f.key, int8(f.num))
+	case StringType:
+		visitor.VisitString(f.key, f.str)
+	case TimeType:
+		visitor.VisitTime(f.key, f.TimeValue())
+	case Uint64Type:
+		visitor.VisitUint64(f.key, uint64(f.num))
+	case Uint32Type:
+		visitor.VisitUint32(f.key, uint32(f.num))
+	case Uint16Type:
+		visitor.VisitUint16(f.key, uint16(f.num))
+	case Uint8Type:
+		visitor.VisitUint8(f.key, uint8(f.num))
+	case UintptrType:
+		visitor.VisitUintptr(f.key, uintptr(f.num))
+	case StringerType:
+		if v, ok := f.iface.(fmt.Stringer); ok && v != nil {
+			visitor.VisitStringer(f.key, v)
+			return
+		}
+		visitor.VisitUnknown(f.key, f.iface)
+	case ErrorType:
+		if v, ok := f.iface.(error); ok && v != nil {
+			visitor.VisitError(f.key, v)
+			return
+		}
+		visitor.VisitUnknown(f.key, f.iface)
+	case StackType:
+		v, _ := f.iface.(*Stacktrace)
+		visitor.VisitStack(f.key, v)
+	case GroupType:
+		v, _ := f.iface.([]Field)
+		visitor.VisitGroup(f.key, v)
+	case NamespaceType:
+		visitor.VisitNamespace(f.key)
+	default:
+		visitor.VisitUnknown(f.key, f.iface)
+	}
+}
+
+// Accept calls the method of visitor matching the FieldType of f, see Visit.
+func (f Attr) Accept(visitor FieldVisitor) {
+	Visit(f, visitor)
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tVisit struct {
+	method string
+	key    string
+	value  any
+}
+
+type tVisitor struct {
+	visits []tVisit
+}
+
+func (t *tVisitor) visit(method, key string, value any) {
+	t.visits = append(t.visits, tVisit{method: method, key: key, value: value})
+}
+
+func (t *tVisitor) VisitUnknown(key string, value any)           { t.visit("Unknown", key, value) }
+func (t *tVisitor) VisitBinary(key string, value []byte)         { t.visit("Binary", key, value) }
+func (t *tVisitor) VisitBool(key string, value bool)             { t.visit("Bool", key, value) }
+func (t *tVisitor) VisitComplex128(key string, v complex128)     { t.visit("Complex128", key, v) }
+func (t *tVisitor) VisitComplex64(key string, v complex64)       { t.visit("Complex64", key, v) }
+func (t *tVisitor) VisitDuration(key string, v time.Duration)    { t.visit("Duration", key, v) }
+func (t *tVisitor) VisitFloat64(key string, value float64)       { t.visit("Float64", key, value) }
+func (t *tVisitor) VisitFloat32(key string, value float32)       { t.visit("Float32", key, value) }
+func (t *tVisitor) VisitInt64(key string, value int64)           { t.visit("Int64", key, value) }
+func (t *tVisitor) VisitInt32(key string, value int32)           { t.visit("Int32", key, value) }
+func (t *tVisitor) VisitInt16(key string, value int16)           { t.visit("Int16", key, value) }
+func (t *tVisitor) VisitInt8(key string, value int8)             { t.visit("Int8", key, value) }
+func (t *tVisitor) VisitString(key string, value string)         { t.visit("String", key, value) }
+func (t *tVisitor) VisitTime(key string, value time.Time)        { t.visit("Time", key, value) }
+func (t *tVisitor) VisitUint64(key string, value uint64)         { t.visit("Uint64", key, value) }
+func (t *tVisitor) VisitUint32(key string, value uint32)         { t.visit("Uint32", key, value) }
+func (t *tVisitor) VisitUint16(key string, value uint16)         { t.visit("Uint16", key, value) }
+func (t *tVisitor) VisitUint8(key string, value uint8)           { t.visit("Uint8", key, value) }
+func (t *tVisitor) VisitUintptr(key string, value uintptr)       { t.visit("Uintptr", key, value) }
+func (t *tVisitor) VisitStringer(key string, value fmt.Stringer) { t.visit("Stringer", key, value) }
+func (t *tVisitor) VisitError(key string, value error)           { t.visit("Error", key, value) }
+func (t *tVisitor) VisitStack(key string, value *Stacktrace)     { t.visit("Stack", key, value) }
+func (t *tVisitor) VisitGroup(key string, value []Field)         { t.visit("Group", key, value) }
+func (t *tVisitor) VisitNamespace(key string)                    { t.visit("Namespace", key, nil) }
+
+func TestVisit(t *testing.T) {
+	now := time.Now()
+	var nilInt *int
+	tests := []struct {
+		field Field
+		want  tVisit
+	}{
+		{Any("k", 1), tVisit{"Unknown", "k", 1}},
+		{Intp("k", nilInt), tVisit{"Unknown", "k", (*int64)(nil)}},
+		{Binary("k", []byte("b")), tVisit{"Binary", "k", []byte("b")}},
+		{Bool("k", true), tVisit{"Bool", "k", true}},
+		{Complex128("k", 1+2i), tVisit{"Complex128", "k", complex128(1 + 2i)}},
+		{Complex64("k", 1+2i), tVisit{"Complex64", "k", complex64(1 + 2i)}},
+		{Duration("k", time.Second), tVisit{"Duration", "k", time.Second}},
+		{Float64("k", 1.5), tVisit{"Float64", "k", 1.5}},
+		{Float32("k", 1.5), tVisit{"Float32", "k", float32(1.5)}},
+		{Int64("k", -1), tVisit{"Int64", "k", int64(-1)}},
+		{Int32("k", -1), tVisit{"Int32", "k", int32(-1)}},
+		{Int16("k", -1), tVisit{"Int16", "k", int16(-1)}},
+		{Int8("k", -1), tVisit{"Int8", "k", int8(-1)}},
+		{String("k", "v"), tVisit{"String", "k", "v"}},
+		{Time("k", now), tVisit{"Time", "k", now.Round(0)}},
+		{Uint64("k", 1), tVisit{"Uint64", "k", uint64(1)}},
+		{Uint32("k", 1), tVisit{"Uint32", "k", uint32(1)}},
+		{Uint16("k", 1), tVisit{"Uint16", "k", uint16(1)}},
+		{Uint8("k", 1), tVisit{"Uint8", "k", uint8(1)}},
+		{Uintptr("k", 1), tVisit{"Uintptr", "k", uintptr(1)}},
+		{Stringer("k", hanBoolStringer(true)), tVisit{"Stringer", "k", hanBoolStringer(true)}},
+		{Stringer("k", nil), tVisit{"Unknown", "k", nil}},
+		{NamedError("k", io.EOF), tVisit{"Error", "k", io.EOF}},
+		{Error(nil), tVisit{"Unknown", "error", nil}},
+		{Group("k", Int("n", 1)), tVisit{"Group", "k", []Field{Int("n", 1)}}},
+		{Namespace("k"), tVisit{"Namespace", "k", nil}},
+		{Lazy("k", func() any { return "v" }), tVisit{"String", "k", "v"}},
+		{Attr{}, tVisit{"Unknown", "", nil}},
+		{Attr{key: "k", typ: FieldType(255)}, tVisit{"Unknown", "k", nil}},
+	}
+	for _, tt := range tests {
+		v := &tVisitor{}
+		Visit(tt.field, v)
+		assert.Equal(t, []tVisit{tt.want}, v.visits)
+	}
+}
+
+func TestField_Accept(t *testing.T) {
+	v := &tVisitor{}
+	Stack("k").Accept(v)
+	assert.Len(t, v.visits, 1)
+	assert.Equal(t, "Stack", v.visits[0].method)
+	assert.NotEmpty(t, v.visits[0].value.(*Stacktrace).Callers())
+
+	v = &tVisitor{}
+	err := errors.New("e")
+	LazyField(func() Field { return NamedError("k", err) }).Accept(v)
+	assert.Equal(t, []tVisit{{"Error", "k", err}}, v.visits)
+}